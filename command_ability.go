@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func commandAbility(ctx context.Context, config *commandConfig, abilityName string) error {
+	if len(abilityName) == 0 {
+		return errors.New("abilityName cannot be empty")
+	}
+
+	ability, err := config.Client.GetAbilityContext(ctx, abilityName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such ability %s", abilityName))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to get ability: %w", err)
+	}
+
+	fmt.Printf("Name: %s\n", ability.Name)
+	shortEffect, effect := ability.Effect(config.Language)
+	if shortEffect != "" {
+		fmt.Printf("Short effect: %s\n", shortEffect)
+	}
+	if effect != "" {
+		fmt.Printf("Effect: %s\n", effect)
+	}
+
+	fmt.Println("Pokemon:")
+	if len(ability.Pokemon) == 0 {
+		fmt.Println("[No entries found]")
+	}
+	for _, pokemon := range ability.Pokemon {
+		if pokemon.IsHidden {
+			fmt.Printf("- %s (hidden)\n", pokemon.Pokemon.Name)
+		} else {
+			fmt.Printf("- %s\n", pokemon.Pokemon.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+func commandCache(_ context.Context, config *commandConfig, args string) error {
+	subcommand, arg, _ := strings.Cut(args, " ")
+
+	switch subcommand {
+	case "stats":
+		return cacheStats(config)
+	case "list":
+		return cacheList(config)
+	case "clear":
+		clearable, ok := config.Cache.(pokecache.ClearableCache)
+		if !ok {
+			return errors.New("cache cannot be cleared")
+		}
+		clearable.Clear()
+		fmt.Println("Cleared the cache")
+		return nil
+	case "evict":
+		if len(arg) == 0 {
+			return errors.New("url cannot be empty")
+		}
+		config.Cache.Delete(arg)
+		fmt.Printf("Evicted %s\n", arg)
+		return nil
+	}
+
+	return errors.New("usage: cache stats | cache list | cache clear | cache evict <url>")
+}
+
+func cacheStats(config *commandConfig) error {
+	stats := config.MemoryCache.Stats()
+
+	hitRate := 0.0
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		hitRate = 100 * float64(stats.Hits) / float64(lookups)
+	}
+
+	fmt.Printf("Entries: %v\n", stats.Entries)
+	fmt.Printf("Bytes: %v\n", stats.Bytes)
+	fmt.Printf("Hits: %v\n", stats.Hits)
+	fmt.Printf("Misses: %v\n", stats.Misses)
+	fmt.Printf("Hit rate: %.1f%%\n", hitRate)
+	fmt.Printf("Evictions: %v\n", stats.Evictions)
+	return nil
+}
+
+func cacheList(config *commandConfig) error {
+	entries := config.MemoryCache.Entries()
+	if len(entries) == 0 {
+		fmt.Println("[No entries found]")
+		return nil
+	}
+
+	for _, entry := range entries {
+		age := time.Since(entry.CreatedAt).Round(time.Second)
+		fmt.Printf("- %s (%v bytes, %v old)\n", entry.Key, entry.Size, age)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func commandEvolutions(ctx context.Context, config *commandConfig, pokemonName string) error {
+	if len(pokemonName) == 0 {
+		return errors.New("pokemonName cannot be empty")
+	}
+
+	chain, err := config.Client.GetPokemonEvolutionChainContext(ctx, pokemonName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such pokemon %s", pokemonName))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to get evolution chain: %w", err)
+	}
+
+	printChainLink(chain.Chain, 0)
+	return nil
+}
+
+func printChainLink(link pokeapi.ChainLink, depth int) {
+	line := strings.Repeat("  ", depth) + "- " + link.Species.Name
+
+	// A species can evolve from its parent in several ways
+	methods := []string{}
+	for _, detail := range link.EvolutionDetails {
+		methods = append(methods, detail.Description())
+	}
+	if len(methods) > 0 {
+		line += " (" + strings.Join(methods, ", or ") + ")"
+	}
+	if link.IsBaby {
+		line += " [baby]"
+	}
+
+	fmt.Println(line)
+	for _, next := range link.EvolvesTo {
+		printChainLink(next, depth+1)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func commandMove(ctx context.Context, config *commandConfig, moveName string) error {
+	if len(moveName) == 0 {
+		return errors.New("moveName cannot be empty")
+	}
+
+	move, err := config.Client.GetMoveContext(ctx, moveName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such move %s", moveName))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to get move: %w", err)
+	}
+
+	printMoveData(move, config.Language)
+	return nil
+}
+
+func printMoveData(move *pokeapi.MoveResult, language string) {
+	fmt.Printf("Name: %s\n", move.Name)
+	fmt.Printf("Type: %s\n", move.Type.Name)
+	fmt.Printf("Damage class: %s\n", move.DamageClass.Name)
+	fmt.Printf("Power: %s\n", optionalStat(move.Power))
+	fmt.Printf("Accuracy: %s\n", optionalStat(move.Accuracy))
+	fmt.Printf("PP: %s\n", optionalStat(move.PP))
+	fmt.Printf("Priority: %d\n", move.Priority)
+	if effect := move.Effect(language); effect != "" {
+		fmt.Printf("Effect: %s\n", effect)
+	}
+	if move.Meta != nil && move.Meta.Ailment.Name != "" && move.Meta.Ailment.Name != "none" {
+		fmt.Printf("Ailment: %s (%d%% chance)\n", move.Meta.Ailment.Name, move.Meta.AilmentChance)
+	}
+}
+
+// optionalStat formats stats PokeAPI leaves null, such as the power of
+// status moves
+func optionalStat(stat *int) string {
+	if stat == nil {
+		return "-"
+	}
+	return fmt.Sprint(*stat)
+}
+
+func printPokemonMoves(data pokeapi.PokemonResult) {
+	fmt.Println("Moves:")
+	groups := data.MoveGroups()
+	if len(groups) == 0 {
+		fmt.Println("[No entries found]")
+	}
+
+	for _, group := range groups {
+		fmt.Printf("%s (%s):\n", group.LearnMethod, group.VersionGroup)
+		for _, move := range group.Moves {
+			if group.LearnMethod == "level-up" {
+				fmt.Printf("- %s (level %d)\n", move.Name, move.Level)
+			} else {
+				fmt.Printf("- %s\n", move.Name)
+			}
+		}
+	}
+}
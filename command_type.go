@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func commandType(ctx context.Context, config *commandConfig, typeName string) error {
+	if len(typeName) == 0 {
+		return errors.New("typeName cannot be empty")
+	}
+
+	result, err := config.Client.GetTypeContext(ctx, typeName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such type %s", typeName))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to get type: %w", err)
+	}
+
+	relations := result.DamageRelations
+	fmt.Printf("Type: %s\n", result.Name)
+	printTypeRelation("Double damage to", relations.DoubleDamageTo)
+	printTypeRelation("Half damage to", relations.HalfDamageTo)
+	printTypeRelation("No damage to", relations.NoDamageTo)
+	printTypeRelation("Double damage from", relations.DoubleDamageFrom)
+	printTypeRelation("Half damage from", relations.HalfDamageFrom)
+	printTypeRelation("No damage from", relations.NoDamageFrom)
+
+	fmt.Println("Pokemon:")
+	names := result.PokemonNames()
+	if len(names) == 0 {
+		fmt.Println("[No entries found]")
+	}
+	for _, name := range names {
+		fmt.Printf("- %s\n", name)
+	}
+
+	return nil
+}
+
+func printTypeRelation(label string, types []pokeapi.NamedResource) {
+	names := []string{}
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+
+	if len(names) == 0 {
+		fmt.Printf("%s: none\n", label)
+		return
+	}
+	fmt.Printf("%s: %s\n", label, strings.Join(names, ", "))
+}
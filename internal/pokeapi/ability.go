@@ -0,0 +1,36 @@
+package pokeapi
+
+import "context"
+
+type AbilityResult struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    NamedResource `json:"language"`
+	} `json:"effect_entries"`
+	Pokemon []struct {
+		IsHidden bool          `json:"is_hidden"`
+		Slot     int           `json:"slot"`
+		Pokemon  NamedResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+func (c *Client) GetAbility(abilityName string) (*AbilityResult, error) {
+	return c.GetAbilityContext(context.Background(), abilityName)
+}
+
+func (c *Client) GetAbilityContext(ctx context.Context, abilityName string) (*AbilityResult, error) {
+	return Fetch[AbilityResult](ctx, c, "ability/"+abilityName)
+}
+
+// Effect returns the short and full effect text in language
+func (a *AbilityResult) Effect(language string) (shortEffect string, effect string) {
+	for _, entry := range a.EffectEntries {
+		if entry.Language.Name == language {
+			return cleanText(entry.ShortEffect), cleanText(entry.Effect)
+		}
+	}
+	return "", ""
+}
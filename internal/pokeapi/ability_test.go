@@ -0,0 +1,57 @@
+package pokeapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+const abilityJson = `{
+	"id": 31,
+	"name": "lightning-rod",
+	"effect_entries": [
+		{"effect": "Redirige les attaques.", "short_effect": "Redirige.", "language": {"name": "fr"}},
+		{"effect": "All other Pokémon's single-target Electric-type moves\nare redirected to this Pokémon.", "short_effect": "Redirects Electric moves.", "language": {"name": "en"}}
+	],
+	"pokemon": [
+		{"is_hidden": true, "slot": 3, "pokemon": {"name": "pikachu"}},
+		{"is_hidden": false, "slot": 1, "pokemon": {"name": "rhyhorn"}}
+	]
+}`
+
+func TestGetAbility(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(abilityJson))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	ability, err := client.GetAbilityContext(context.Background(), "lightning-rod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/ability/lightning-rod" {
+		t.Errorf("expected /ability/lightning-rod, got %s", gotPath)
+	}
+
+	shortEffect, effect := ability.Effect("en")
+	if shortEffect != "Redirects Electric moves." {
+		t.Errorf("unexpected short effect %q", shortEffect)
+	}
+	if effect != "All other Pokémon's single-target Electric-type moves are redirected to this Pokémon." {
+		t.Errorf("unexpected effect %q", effect)
+	}
+	if shortEffect, effect := ability.Effect("de"); shortEffect != "" || effect != "" {
+		t.Errorf("expected no effect in de, got %q %q", shortEffect, effect)
+	}
+
+	if len(ability.Pokemon) != 2 || !ability.Pokemon[0].IsHidden || ability.Pokemon[1].Pokemon.Name != "rhyhorn" {
+		t.Errorf("unexpected pokemon: %+v", ability.Pokemon)
+	}
+}
@@ -0,0 +1,323 @@
+package pokeapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+const DefaultBaseURL = "https://pokeapi.co/api/v2/"
+const DefaultUserAgent = "pokedexgo"
+
+// DefaultNotFoundTTL is how long a 404 is remembered, so mistyped names
+// don't hit the network every time
+const DefaultNotFoundTTL = 5 * time.Minute
+
+// DefaultRevalidateTimeout caps a background refresh, which no caller is
+// waiting on to give up
+const DefaultRevalidateTimeout = 30 * time.Second
+
+// Negative cache entries are stored under the URL itself, so a lookup is a
+// single cache hit or miss and evicting the URL forgets the 404 too. The
+// marker is never valid JSON, so it can't be mistaken for a real response.
+var notFoundMarker = []byte("\x00not-found")
+
+// DefaultTTLs are how long responses are cached for, by resource. Resources
+// not listed use the cache's own default.
+var DefaultTTLs = map[string]time.Duration{
+	"location-area": 24 * time.Hour,
+	"pokemon":       24 * time.Hour,
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      pokecache.Cache
+	userAgent  string
+	timeout    time.Duration
+
+	ttls              map[string]time.Duration
+	notFoundTTL       time.Duration
+	revalidateTimeout time.Duration
+	retryPolicy       RetryPolicy
+	limiter           *rateLimiter
+	flight            flightGroup
+}
+
+type Option func(*Client)
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithCache(cache pokecache.Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithTTL sets how long responses for resource, such as "pokemon", are
+// cached for
+func WithTTL(resource string, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ttls[resource] = ttl
+	}
+}
+
+// WithNotFoundTTL sets how long a 404 is cached for, 0 to disable
+func WithNotFoundTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.notFoundTTL = ttl
+	}
+}
+
+// WithRevalidateTimeout sets how long a background refresh of a stale
+// entry may take, retries included
+func WithRevalidateTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.revalidateTimeout = timeout
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func NewClient(options ...Option) *Client {
+	client := Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+		cache:      pokecache.NoopCache{},
+		userAgent:  DefaultUserAgent,
+
+		ttls:              make(map[string]time.Duration),
+		notFoundTTL:       DefaultNotFoundTTL,
+		revalidateTimeout: DefaultRevalidateTimeout,
+		retryPolicy:       NoRetry,
+	}
+
+	for resource, ttl := range DefaultTTLs {
+		client.ttls[resource] = ttl
+	}
+
+	for _, option := range options {
+		option(&client)
+	}
+
+	if !strings.HasSuffix(client.baseURL, "/") {
+		client.baseURL += "/"
+	}
+
+	// Copy the http.Client so a timeout never leaks into a caller's client
+	if client.timeout > 0 {
+		httpClient := *client.httpClient
+		httpClient.Timeout = client.timeout
+		client.httpClient = &httpClient
+	}
+
+	return &client
+}
+
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+func (c *Client) LocationsURL() string {
+	return c.endpoint("location-area/")
+}
+
+func (c *Client) endpoint(path string) string {
+	return c.baseURL + path
+}
+
+// ttl returns the cache TTL for rawURL based on its resource, the first
+// path segment after the base URL. The query, such as pagination, is ignored.
+func (c *Client) ttl(rawURL string) time.Duration {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+
+	basePath := ""
+	if base, err := url.Parse(c.baseURL); err == nil {
+		basePath = base.Path
+	}
+
+	path := strings.TrimPrefix(parsed.Path, basePath)
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return c.ttls[resource]
+}
+
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	cachedValue, ok := c.cache.Get(url)
+	if ok && bytes.Equal(cachedValue, notFoundMarker) {
+		return nil, &StatusError{StatusCode: http.StatusNotFound, URL: url}
+	}
+	if ok {
+		return cachedValue, nil
+	}
+
+	if stale, ok := c.cache.(pokecache.StaleCache); ok {
+		if staleValue, ok := stale.GetStale(url); ok && !bytes.Equal(staleValue, notFoundMarker) {
+			go c.revalidate(url, staleValue)
+			return staleValue, nil
+		}
+	}
+
+	// An expired entry with validators can still be renewed by a
+	// conditional request, saving the download if it hasn't changed
+	var expired []byte
+	if validatorCache, ok := c.cache.(pokecache.ValidatorCache); ok {
+		expired, _ = validatorCache.GetExpired(url)
+	}
+
+	return c.flight.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		return c.fetchWithRetry(ctx, url, expired)
+	})
+}
+
+// revalidate refreshes a stale cache entry in the background. It outlives
+// the request that triggered it, so it doesn't use that request's context,
+// but has its own deadline so a hung server can't keep it alive forever.
+func (c *Client) revalidate(url string, stale []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.revalidateTimeout)
+	defer cancel()
+
+	c.flight.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		return c.fetchWithRetry(ctx, url, stale)
+	})
+}
+
+type response struct {
+	body        []byte
+	validators  pokecache.Validators
+	notModified bool
+}
+
+// fetchWithRetry fetches url and caches the result. If stale is set, the
+// request is made conditional on the validators cached with it, and a 304
+// renews the stale entry instead of downloading it again.
+func (c *Client) fetchWithRetry(ctx context.Context, url string, stale []byte) ([]byte, error) {
+	var validators pokecache.Validators
+	validatorCache, hasValidators := c.cache.(pokecache.ValidatorCache)
+	if stale != nil && hasValidators {
+		validators, _ = validatorCache.GetValidators(url)
+	}
+
+	var resp *response
+	var err error
+	for attempt := 1; ; attempt++ {
+		resp, err = c.fetch(ctx, url, validators)
+		if err == nil || attempt >= c.retryPolicy.MaxAttempts || !isRetryable(err) {
+			break
+		}
+
+		delay := c.retryPolicy.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			delay = statusErr.RetryAfter
+		}
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return nil, fmt.Errorf("Request error: %w", sleepErr)
+		}
+	}
+	if errors.Is(err, ErrNotFound) && c.notFoundTTL > 0 {
+		c.cache.AddWithTTL(url, notFoundMarker, c.notFoundTTL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.notModified {
+		resp.body = stale
+		if resp.validators.IsZero() {
+			resp.validators = validators
+		}
+	}
+
+	if hasValidators {
+		validatorCache.AddWithValidators(url, resp.body, c.ttl(url), resp.validators)
+	} else {
+		c.cache.AddWithTTL(url, resp.body, c.ttl(url))
+	}
+
+	return resp.body, nil
+}
+
+func (c *Client) fetch(ctx context.Context, url string, validators pokecache.Validators) (*response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("Request error: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Request error: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Request error: %w", err)
+	}
+
+	defer resp.Body.Close()
+	result := response{
+		validators: pokecache.Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}
+
+	if resp.StatusCode == http.StatusNotModified && !validators.IsZero() {
+		result.notModified = true
+		return &result, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	result.body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Body parsing error: %w", err)
+	}
+
+	return &result, nil
+}
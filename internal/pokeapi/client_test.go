@@ -0,0 +1,402 @@
+package pokeapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+func TestClientBaseURL(t *testing.T) {
+	var gotPath, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"name": "pikachu", "base_experience": 112}`))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL+"/api/v2"),
+		pokeapi.WithUserAgent("pokedexgo-test"),
+	)
+
+	result, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "pikachu" || result.BaseExperience != 112 {
+		t.Errorf("unexpected result: %s %d", result.Name, result.BaseExperience)
+	}
+	if gotPath != "/api/v2/pokemon/pikachu" {
+		t.Errorf("expected path /api/v2/pokemon/pikachu, got %s", gotPath)
+	}
+	if gotUserAgent != "pokedexgo-test" {
+		t.Errorf("expected user agent pokedexgo-test, got %s", gotUserAgent)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"pokemon_encounters": [{"pokemon": {"name": "tentacool"}}]}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+	)
+
+	for i := 0; i < 3; i++ {
+		names, err := client.GetLocationPokemon("canalave-city-area")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(names) != 1 || names[0] != "tentacool" {
+			t.Errorf("unexpected names: %v", names)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithHTTPClient(httpClient),
+		pokeapi.WithTimeout(5*time.Millisecond),
+	)
+
+	_, err := client.GetPokemon("pikachu")
+	if err == nil {
+		t.Errorf("expected timeout error")
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("expected caller's http.Client to be left untouched")
+	}
+}
+
+func TestClientStatusErrors(t *testing.T) {
+	cases := []struct {
+		status int
+		target error
+	}{
+		{status: http.StatusNotFound, target: pokeapi.ErrNotFound},
+		{status: http.StatusTooManyRequests, target: pokeapi.ErrRateLimited},
+		{status: http.StatusInternalServerError, target: pokeapi.ErrServer},
+		{status: http.StatusBadGateway, target: pokeapi.ErrServer},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				w.Write([]byte("Not Found"))
+			}))
+			defer server.Close()
+
+			cache := pokecache.NewCache(time.Minute)
+			defer cache.Close()
+			// Negative caching is covered by TestClientNegativeCache
+			client := pokeapi.NewClient(
+				pokeapi.WithBaseURL(server.URL),
+				pokeapi.WithCache(cache),
+				pokeapi.WithNotFoundTTL(0),
+			)
+
+			_, err := client.GetPokemon("notapokemon")
+			if !errors.Is(err, c.target) {
+				t.Errorf("expected %v, got %v", c.target, err)
+			}
+
+			var statusErr *pokeapi.StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected a StatusError, got %v", err)
+			}
+			if statusErr.StatusCode != c.status {
+				t.Errorf("expected status %d, got %d", c.status, statusErr.StatusCode)
+			}
+			if statusErr.URL != server.URL+"/pokemon/notapokemon" {
+				t.Errorf("unexpected URL %s", statusErr.URL)
+			}
+
+			if _, ok := cache.Get(statusErr.URL); ok {
+				t.Errorf("expected error response to not be cached")
+			}
+		})
+	}
+}
+
+func TestClientContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetPokemonContext(ctx, "pikachu")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClientTTL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("pokemon", time.Millisecond),
+	)
+
+	client.GetPokemon("pikachu")
+	time.Sleep(5 * time.Millisecond)
+	client.GetPokemon("pikachu")
+
+	if requests != 2 {
+		t.Errorf("expected the pokemon TTL to expire the entry, got %d requests", requests)
+	}
+
+	entries := cache.Entries()
+	if len(entries) != 1 || entries[0].ExpiresAt.Sub(entries[0].CreatedAt) != time.Millisecond {
+		t.Errorf("expected entry to expire after 1ms, got %+v", entries)
+	}
+}
+
+func TestClientTTLIgnoresQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL+"/api/v2"),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("location-area", time.Hour),
+	)
+
+	// PokeAPI pagination links have no slash before the query
+	next := client.BaseURL() + "location-area?offset=20&limit=20"
+	if _, err := client.GetLocations(&next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := cache.Entries()
+	if len(entries) != 1 || entries[0].ExpiresAt.Sub(entries[0].CreatedAt) != time.Hour {
+		t.Errorf("expected paginated entry to use the location-area TTL, got %+v", entries)
+	}
+}
+
+func TestClientStaleWhileRevalidate(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name": "pikachu", "order": %d}`, version.Load())
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour, pokecache.WithStaleWhileRevalidate(time.Hour))
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("pokemon", 5*time.Millisecond),
+	)
+
+	result, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Order != 1 {
+		t.Fatalf("expected order 1, got %d", result.Order)
+	}
+
+	version.Store(2)
+	time.Sleep(10 * time.Millisecond)
+
+	// The stale entry is served right away while it is refreshed
+	result, err = client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Order != 1 {
+		t.Errorf("expected stale order 1, got %d", result.Order)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := cache.Get(server.URL + "/pokemon/pikachu"); ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	result, err = client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Order != 2 {
+		t.Errorf("expected refreshed order 2, got %d", result.Order)
+	}
+}
+
+func TestClientRevalidateTimeout(t *testing.T) {
+	var requests atomic.Int32
+	abandoned := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Write([]byte(`{"name": "pikachu"}`))
+			return
+		}
+
+		// The refresh hangs until the client gives up on it
+		<-r.Context().Done()
+		close(abandoned)
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour, pokecache.WithStaleWhileRevalidate(time.Hour))
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("pokemon", time.Millisecond),
+		pokeapi.WithRevalidateTimeout(20*time.Millisecond),
+	)
+
+	if _, err := client.GetPokemon("pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// Served stale, and starts the refresh that hangs
+	if _, err := client.GetPokemon("pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-abandoned:
+	case <-time.After(time.Second):
+		t.Errorf("expected the background refresh to time out")
+	}
+}
+
+func TestClientNegativeCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/pokemon/pikachu" {
+			w.Write([]byte(`{"name": "pikachu"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithNotFoundTTL(time.Hour),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetPokemon("pikachuu")
+		if !errors.Is(err, pokeapi.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+
+	// Each lookup is a single hit or miss, the 404 doesn't add a lookup
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("expected 2 hits and 1 miss, got %+v", stats)
+	}
+
+	// Evicting the URL forgets the 404
+	cache.Delete(server.URL + "/pokemon/pikachuu")
+	client.GetPokemon("pikachuu")
+	if requests.Load() != 2 {
+		t.Errorf("expected a request after evicting the not found entry, got %d", requests.Load())
+	}
+
+	result, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "pikachu" {
+		t.Errorf("unexpected result: %s", result.Name)
+	}
+}
+
+func TestClientNegativeCacheExpiry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithNotFoundTTL(time.Millisecond),
+	)
+
+	client.GetPokemon("pikachuu")
+
+	// Only needs to be long enough for the 1ms entry to expire
+	time.Sleep(20 * time.Millisecond)
+
+	client.GetPokemon("pikachuu")
+	if requests.Load() != 2 {
+		t.Errorf("expected the not found entry to expire, got %d requests", requests.Load())
+	}
+}
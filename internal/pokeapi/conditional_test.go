@@ -0,0 +1,153 @@
+package pokeapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+// waitForRevalidation waits on the server rather than the cache, since
+// the renewed entry may already be stale again by the time it is checked
+func waitForRevalidation(t *testing.T, notModified *atomic.Int32, key string) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if notModified.Load() == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %s to be revalidated", key)
+}
+
+func TestConditionalRequests(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	var fullResponses, notModified atomic.Int32
+	var gotIfModifiedSince atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		if r.Header.Get("If-None-Match") == etag {
+			gotIfModifiedSince.Store(r.Header.Get("If-Modified-Since"))
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses.Add(1)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour, pokecache.WithStaleWhileRevalidate(time.Hour))
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("pokemon", 5*time.Millisecond),
+	)
+	url := server.URL + "/pokemon/pikachu"
+
+	if _, err := client.GetPokemon("pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validators, ok := cache.GetValidators(url)
+	if !ok || validators.ETag != etag || validators.LastModified != lastModified {
+		t.Fatalf("expected validators to be cached, got %+v", validators)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	renewedAfter := time.Now()
+
+	result, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "pikachu" {
+		t.Errorf("unexpected result: %s", result.Name)
+	}
+
+	waitForRevalidation(t, &notModified, url)
+
+	if fullResponses.Load() != 1 {
+		t.Errorf("expected 1 full response, got %d", fullResponses.Load())
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("expected 1 not modified response, got %d", notModified.Load())
+	}
+	if got := gotIfModifiedSince.Load(); got != lastModified {
+		t.Errorf("expected If-Modified-Since %s, got %v", lastModified, got)
+	}
+
+	// The 304 is counted before the entry is renewed, so wait for the
+	// renewal to land. It only stays fresh for 5ms, so it may be stale again.
+	deadline := time.Now().Add(time.Second)
+	for {
+		entries := cache.Entries()
+		if len(entries) == 1 && entries[0].CreatedAt.After(renewedAfter) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to be renewed", url)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	val, ok := cache.Get(url)
+	if !ok {
+		val, _ = cache.GetStale(url)
+	}
+	if string(val) != `{"name": "pikachu"}` {
+		t.Errorf("expected renewed entry to keep its body, got %s", val)
+	}
+}
+
+func TestConditionalRequestsWithoutStaleWindow(t *testing.T) {
+	const etag = `"v1"`
+
+	var fullResponses, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses.Add(1)
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Hour)
+	defer cache.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTTL("pokemon", 5*time.Millisecond),
+	)
+
+	if _, err := client.GetPokemon("pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	// Without a stale window the expired entry is renewed before returning
+	result, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "pikachu" {
+		t.Errorf("unexpected result: %s", result.Name)
+	}
+	if fullResponses.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("expected 1 full and 1 not modified response, got %d and %d", fullResponses.Load(), notModified.Load())
+	}
+}
@@ -0,0 +1,159 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type EvolutionChainResult struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+// ChainLink is a species in an evolution chain, with the details of how it
+// evolves from its parent and the species it evolves into
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+type EvolutionDetail struct {
+	Trigger               NamedResource  `json:"trigger"`
+	Item                  *NamedResource `json:"item"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	Gender                *int           `json:"gender"`
+	MinLevel              *int           `json:"min_level"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	TimeOfDay             string         `json:"time_of_day"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+func (c *Client) GetEvolutionChain(chainURL string) (*EvolutionChainResult, error) {
+	return c.GetEvolutionChainContext(context.Background(), chainURL)
+}
+
+func (c *Client) GetEvolutionChainContext(ctx context.Context, chainURL string) (*EvolutionChainResult, error) {
+	return FetchURL[EvolutionChainResult](ctx, c, chainURL)
+}
+
+func (c *Client) GetPokemonEvolutionChain(pokemonName string) (*EvolutionChainResult, error) {
+	return c.GetPokemonEvolutionChainContext(context.Background(), pokemonName)
+}
+
+// GetPokemonEvolutionChainContext follows pokemon -> species ->
+// evolution chain
+func (c *Client) GetPokemonEvolutionChainContext(ctx context.Context, pokemonName string) (*EvolutionChainResult, error) {
+	pokemon, err := c.GetPokemonContext(ctx, pokemonName)
+	if err != nil {
+		return nil, err
+	}
+
+	species, err := c.GetPokemonSpeciesContext(ctx, pokemon)
+	if err != nil {
+		return nil, err
+	}
+
+	if species.EvolutionChain.URL == "" {
+		return nil, fmt.Errorf("%s has no evolution chain", species.Name)
+	}
+
+	return c.GetEvolutionChainContext(ctx, species.EvolutionChain.URL)
+}
+
+// Description summarizes the conditions of an evolution, such as
+// "level 16" or "trade holding metal-coat"
+func (d EvolutionDetail) Description() string {
+	conditions := []string{}
+
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel != nil {
+			conditions = append(conditions, fmt.Sprintf("level %d", *d.MinLevel))
+		} else {
+			conditions = append(conditions, "level up")
+		}
+	case "use-item":
+		if d.Item != nil {
+			conditions = append(conditions, "use "+d.Item.Name)
+		} else {
+			conditions = append(conditions, "use item")
+		}
+	case "trade":
+		conditions = append(conditions, "trade")
+		if d.TradeSpecies != nil {
+			conditions = append(conditions, "for "+d.TradeSpecies.Name)
+		}
+	default:
+		conditions = append(conditions, strings.ReplaceAll(d.Trigger.Name, "-", " "))
+	}
+
+	if d.HeldItem != nil {
+		conditions = append(conditions, "holding "+d.HeldItem.Name)
+	}
+	if d.MinHappiness != nil {
+		conditions = append(conditions, fmt.Sprintf("with happiness %d", *d.MinHappiness))
+	}
+	if d.MinAffection != nil {
+		conditions = append(conditions, fmt.Sprintf("with affection %d", *d.MinAffection))
+	}
+	if d.MinBeauty != nil {
+		conditions = append(conditions, fmt.Sprintf("with beauty %d", *d.MinBeauty))
+	}
+	if d.KnownMove != nil {
+		conditions = append(conditions, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		conditions = append(conditions, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		conditions = append(conditions, "at "+d.Location.Name)
+	}
+	if d.PartySpecies != nil {
+		conditions = append(conditions, "with "+d.PartySpecies.Name+" in party")
+	}
+	if d.PartyType != nil {
+		conditions = append(conditions, "with a "+d.PartyType.Name+" type in party")
+	}
+	if d.Gender != nil {
+		// PokeAPI genders: 1 is female, 2 is male
+		if *d.Gender == 1 {
+			conditions = append(conditions, "female")
+		} else if *d.Gender == 2 {
+			conditions = append(conditions, "male")
+		}
+	}
+	if d.RelativePhysicalStats != nil {
+		switch *d.RelativePhysicalStats {
+		case 1:
+			conditions = append(conditions, "attack above defense")
+		case 0:
+			conditions = append(conditions, "attack equal to defense")
+		case -1:
+			conditions = append(conditions, "attack below defense")
+		}
+	}
+	if d.TimeOfDay != "" {
+		conditions = append(conditions, "during the "+d.TimeOfDay)
+	}
+	if d.NeedsOverworldRain {
+		conditions = append(conditions, "while raining")
+	}
+	if d.TurnUpsideDown {
+		conditions = append(conditions, "with the console upside down")
+	}
+
+	return strings.Join(conditions, " ")
+}
@@ -0,0 +1,104 @@
+package pokeapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+const evolutionChainJson = `{
+	"id": 67,
+	"chain": {
+		"is_baby": false,
+		"species": {"name": "eevee"},
+		"evolution_details": [],
+		"evolves_to": [
+			{
+				"species": {"name": "vaporeon"},
+				"evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}],
+				"evolves_to": []
+			},
+			{
+				"species": {"name": "espeon"},
+				"evolution_details": [{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "day"}],
+				"evolves_to": []
+			}
+		]
+	}
+}`
+
+func TestGetPokemonEvolutionChain(t *testing.T) {
+	paths := []string{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/pokemon/eevee":
+			w.Write([]byte(`{"name": "eevee", "species": {"name": "eevee", "url": "` + server.URL + `/pokemon-species/133/"}}`))
+		case "/pokemon-species/133/":
+			w.Write([]byte(`{"name": "eevee", "evolution_chain": {"url": "` + server.URL + `/evolution-chain/67/"}}`))
+		case "/evolution-chain/67/":
+			w.Write([]byte(evolutionChainJson))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	chain, err := client.GetPokemonEvolutionChainContext(context.Background(), "eevee")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Errorf("expected pokemon, species and chain requests, got %v", paths)
+	}
+
+	if chain.ID != 67 || chain.Chain.Species.Name != "eevee" || len(chain.Chain.EvolvesTo) != 2 {
+		t.Fatalf("unexpected chain: %+v", chain)
+	}
+
+	expected := []string{"use water-stone", "level up with happiness 160 during the day"}
+	for i, next := range chain.Chain.EvolvesTo {
+		if len(next.EvolutionDetails) != 1 {
+			t.Fatalf("expected one evolution detail for %s", next.Species.Name)
+		}
+		if description := next.EvolutionDetails[0].Description(); description != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], description)
+		}
+	}
+}
+
+func TestEvolutionDetailDescription(t *testing.T) {
+	level := 16
+	cases := []struct {
+		detail   pokeapi.EvolutionDetail
+		expected string
+	}{
+		{
+			detail:   pokeapi.EvolutionDetail{Trigger: pokeapi.NamedResource{Name: "level-up"}, MinLevel: &level},
+			expected: "level 16",
+		},
+		{
+			detail: pokeapi.EvolutionDetail{
+				Trigger:  pokeapi.NamedResource{Name: "trade"},
+				HeldItem: &pokeapi.NamedResource{Name: "metal-coat"},
+			},
+			expected: "trade holding metal-coat",
+		},
+		{
+			detail:   pokeapi.EvolutionDetail{Trigger: pokeapi.NamedResource{Name: "shed"}},
+			expected: "shed",
+		},
+	}
+
+	for _, c := range cases {
+		if description := c.detail.Description(); description != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, description)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Fetch retrieves the resource at path, relative to the client's base URL,
+// and decodes it into a T
+func Fetch[T any](ctx context.Context, c *Client, path string) (*T, error) {
+	return FetchURL[T](ctx, c, c.endpoint(path))
+}
+
+// FetchURL is like Fetch but takes an absolute URL, such as the
+// next/previous links of a paginated response
+func FetchURL[T any](ctx context.Context, c *Client, url string) (*T, error) {
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJson[T](body)
+}
+
+func parseJson[T any](body []byte) (*T, error) {
+	var result T
+	err := json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("Json parsing error: %w", err)
+	}
+
+	return &result, nil
+}
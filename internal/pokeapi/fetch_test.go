@@ -0,0 +1,55 @@
+package pokeapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/berry/cheri" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "cheri", "growth_time": 3}`))
+	}))
+	defer server.Close()
+
+	type berry struct {
+		Name       string `json:"name"`
+		GrowthTime int    `json:"growth_time"`
+	}
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	result, err := pokeapi.Fetch[berry](context.Background(), client, "berry/cheri")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "cheri" || result.GrowthTime != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	_, err = pokeapi.FetchURL[berry](context.Background(), client, server.URL+"/berry/cheri")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchInvalidJson(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	_, err := pokeapi.Fetch[pokeapi.PokemonResult](context.Background(), client, "pokemon/pikachu")
+	if err == nil {
+		t.Errorf("expected json parsing error")
+	}
+}
@@ -0,0 +1,130 @@
+package pokeapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type MoveResult struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	Accuracy      *int          `json:"accuracy"`
+	Power         *int          `json:"power"`
+	PP            *int          `json:"pp"`
+	Priority      int           `json:"priority"`
+	EffectChance  *int          `json:"effect_chance"`
+	DamageClass   NamedResource `json:"damage_class"`
+	Type          NamedResource `json:"type"`
+	Meta          *MoveMeta     `json:"meta"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    NamedResource `json:"language"`
+	} `json:"effect_entries"`
+}
+
+type MoveMeta struct {
+	Ailment       NamedResource `json:"ailment"`
+	AilmentChance int           `json:"ailment_chance"`
+	Category      NamedResource `json:"category"`
+	MinHits       *int          `json:"min_hits"`
+	MaxHits       *int          `json:"max_hits"`
+	MinTurns      *int          `json:"min_turns"`
+	MaxTurns      *int          `json:"max_turns"`
+	Drain         int           `json:"drain"`
+	Healing       int           `json:"healing"`
+	CritRate      int           `json:"crit_rate"`
+	FlinchChance  int           `json:"flinch_chance"`
+	StatChance    int           `json:"stat_chance"`
+}
+
+func (c *Client) GetMove(moveName string) (*MoveResult, error) {
+	return c.GetMoveContext(context.Background(), moveName)
+}
+
+func (c *Client) GetMoveContext(ctx context.Context, moveName string) (*MoveResult, error) {
+	return Fetch[MoveResult](ctx, c, "move/"+moveName)
+}
+
+// Effect returns the short effect text in language, with the move's
+// effect chance filled in
+func (m *MoveResult) Effect(language string) string {
+	for _, entry := range m.EffectEntries {
+		if entry.Language.Name != language {
+			continue
+		}
+
+		effect := entry.ShortEffect
+		if effect == "" {
+			effect = entry.Effect
+		}
+		if m.EffectChance != nil {
+			effect = strings.ReplaceAll(effect, "$effect_chance", strconv.Itoa(*m.EffectChance))
+		}
+		return cleanText(effect)
+	}
+	return ""
+}
+
+type LearnedMove struct {
+	Name  string
+	Level int
+}
+
+// MoveGroup is the moves a pokemon learns by one method in one version
+// group, such as "level-up" in "red-blue"
+type MoveGroup struct {
+	LearnMethod  string
+	VersionGroup string
+	Moves        []LearnedMove
+}
+
+// MoveGroups groups the pokemon's learnable moves by learn method and
+// version group. Moves are sorted by level, then name.
+func (p *PokemonResult) MoveGroups() []MoveGroup {
+	type groupKey struct {
+		learnMethod  string
+		versionGroup string
+	}
+
+	groups := map[groupKey][]LearnedMove{}
+	for _, move := range p.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			key := groupKey{
+				learnMethod:  detail.MoveLearnMethod.Name,
+				versionGroup: detail.VersionGroup.Name,
+			}
+			groups[key] = append(groups[key], LearnedMove{
+				Name:  move.Move.Name,
+				Level: detail.LevelLearnedAt,
+			})
+		}
+	}
+
+	result := []MoveGroup{}
+	for key, moves := range groups {
+		sort.Slice(moves, func(i, j int) bool {
+			if moves[i].Level != moves[j].Level {
+				return moves[i].Level < moves[j].Level
+			}
+			return moves[i].Name < moves[j].Name
+		})
+
+		result = append(result, MoveGroup{
+			LearnMethod:  key.learnMethod,
+			VersionGroup: key.versionGroup,
+			Moves:        moves,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LearnMethod != result[j].LearnMethod {
+			return result[i].LearnMethod < result[j].LearnMethod
+		}
+		return result[i].VersionGroup < result[j].VersionGroup
+	})
+
+	return result
+}
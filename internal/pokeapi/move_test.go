@@ -0,0 +1,109 @@
+package pokeapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+const moveJson = `{
+	"id": 85,
+	"name": "thunderbolt",
+	"accuracy": 100,
+	"power": 90,
+	"pp": 15,
+	"priority": 0,
+	"effect_chance": 10,
+	"damage_class": {"name": "special"},
+	"type": {"name": "electric"},
+	"meta": {"ailment": {"name": "paralysis"}, "ailment_chance": 10, "category": {"name": "damage+ailment"}},
+	"effect_entries": [
+		{"effect": "Inflicts regular damage.", "short_effect": "Has a $effect_chance%\nchance to paralyze the target.", "language": {"name": "en"}}
+	]
+}`
+
+func TestGetMove(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(moveJson))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	move, err := client.GetMoveContext(context.Background(), "thunderbolt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/move/thunderbolt" {
+		t.Errorf("expected /move/thunderbolt, got %s", gotPath)
+	}
+
+	if move.Power == nil || *move.Power != 90 || move.Accuracy == nil || *move.Accuracy != 100 {
+		t.Errorf("unexpected power or accuracy: %+v", move)
+	}
+	if move.DamageClass.Name != "special" || move.Type.Name != "electric" {
+		t.Errorf("unexpected damage class or type: %+v", move)
+	}
+	if move.Meta == nil || move.Meta.Ailment.Name != "paralysis" || move.Meta.AilmentChance != 10 {
+		t.Errorf("unexpected meta: %+v", move.Meta)
+	}
+
+	expected := "Has a 10% chance to paralyze the target."
+	if effect := move.Effect("en"); effect != expected {
+		t.Errorf("expected %q, got %q", expected, effect)
+	}
+	if effect := move.Effect("de"); effect != "" {
+		t.Errorf("expected no effect in de, got %q", effect)
+	}
+}
+
+func TestMoveGroups(t *testing.T) {
+	var pokemon pokeapi.PokemonResult
+	err := json.Unmarshal([]byte(`{"moves": [
+		{"move": {"name": "thunderbolt"}, "version_group_details": [
+			{"level_learned_at": 0, "move_learn_method": {"name": "machine"}, "version_group": {"name": "red-blue"}}
+		]},
+		{"move": {"name": "thunder-shock"}, "version_group_details": [
+			{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue"}},
+			{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "yellow"}}
+		]},
+		{"move": {"name": "growl"}, "version_group_details": [
+			{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue"}}
+		]},
+		{"move": {"name": "thunder-wave"}, "version_group_details": [
+			{"level_learned_at": 9, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue"}}
+		]}
+	]}`), &pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	groups := pokemon.MoveGroups()
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", groups)
+	}
+
+	first := groups[0]
+	if first.LearnMethod != "level-up" || first.VersionGroup != "red-blue" {
+		t.Errorf("unexpected first group %s/%s", first.LearnMethod, first.VersionGroup)
+	}
+	expected := []pokeapi.LearnedMove{{Name: "growl", Level: 1}, {Name: "thunder-shock", Level: 1}, {Name: "thunder-wave", Level: 9}}
+	if len(first.Moves) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, first.Moves)
+	}
+	for i := range expected {
+		if first.Moves[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], first.Moves[i])
+		}
+	}
+
+	if groups[1].VersionGroup != "yellow" || groups[2].LearnMethod != "machine" {
+		t.Errorf("unexpected group order: %+v", groups)
+	}
+}
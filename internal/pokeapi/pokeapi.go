@@ -1,12 +1,8 @@
 package pokeapi
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"github.com/PFrek/pokedexgo/internal/pokecache"
-	"io"
-	"net/http"
 )
 
 type Location struct {
@@ -27,70 +23,24 @@ func PrintLocationNames(locations []Location) {
 	}
 }
 
-func GetLocations(pageUrl *string, cache *pokecache.Cache) (*LocationsResult, error) {
-	url := "https://pokeapi.co/api/v2/location-area/"
-	if pageUrl != nil {
-		url = *pageUrl
-	}
-
-	cachedValue, ok := cache.Get(url)
-	if ok {
-		return parseLocationsJson(cachedValue)
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Request error: %v", err))
-	}
-
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Body parsing error: %v", err))
-	}
-
-	cache.Add(url, body)
-
-	return parseLocationsJson(body)
+func (c *Client) GetLocations(pageUrl *string) (*LocationsResult, error) {
+	return c.GetLocationsContext(context.Background(), pageUrl)
 }
 
-func parseLocationsJson(body []byte) (*LocationsResult, error) {
-	var result LocationsResult
-	err := json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Json parsing error: %v", err))
+func (c *Client) GetLocationsContext(ctx context.Context, pageUrl *string) (*LocationsResult, error) {
+	if pageUrl != nil {
+		return FetchURL[LocationsResult](ctx, c, *pageUrl)
 	}
 
-	return &result, nil
+	return Fetch[LocationsResult](ctx, c, "location-area/")
 }
 
-func GetLocationPokemon(locationName string, cache *pokecache.Cache) ([]string, error) {
-	url := "https://pokeapi.co/api/v2/location-area/" + locationName
-
-	cachedValue, ok := cache.Get(url)
-	if ok {
-		result, err := parseLocationJson(cachedValue)
-		if err != nil {
-			return nil, err
-		}
-
-		return extractPokemonNames(result), nil
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Request error: %v", err))
-	}
-
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Body parsing error: %v", err))
-	}
-
-	cache.Add(url, body)
+func (c *Client) GetLocationPokemon(locationName string) ([]string, error) {
+	return c.GetLocationPokemonContext(context.Background(), locationName)
+}
 
-	result, err := parseLocationJson(body)
+func (c *Client) GetLocationPokemonContext(ctx context.Context, locationName string) ([]string, error) {
+	result, err := Fetch[LocationResult](ctx, c, "location-area/"+locationName)
 	if err != nil {
 		return nil, err
 	}
@@ -98,16 +48,6 @@ func GetLocationPokemon(locationName string, cache *pokecache.Cache) ([]string,
 	return extractPokemonNames(result), nil
 }
 
-func parseLocationJson(body []byte) (*LocationResult, error) {
-	var result LocationResult
-	err := json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Json parsing error: %v", err))
-	}
-
-	return &result, nil
-}
-
 func extractPokemonNames(results *LocationResult) []string {
 	pokemon := []string{}
 	encounters := results.PokemonEncounters
@@ -119,39 +59,12 @@ func extractPokemonNames(results *LocationResult) []string {
 	return pokemon
 }
 
-func GetPokemon(pokemonName string, cache *pokecache.Cache) (*PokemonResult, error) {
-	url := "https://pokeapi.co/api/v2/pokemon/" + pokemonName
-
-	cachedValue, ok := cache.Get(url)
-	if ok {
-		return parsePokemonJson(cachedValue)
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Request error: %v", err))
-	}
-
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Body parsing error: %v", err))
-	}
-
-	cache.Add(url, body)
-
-	return parsePokemonJson(body)
-
+func (c *Client) GetPokemon(pokemonName string) (*PokemonResult, error) {
+	return c.GetPokemonContext(context.Background(), pokemonName)
 }
 
-func parsePokemonJson(body []byte) (*PokemonResult, error) {
-	var result PokemonResult
-	err := json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Json parsing error: %v", err))
-	}
-
-	return &result, nil
+func (c *Client) GetPokemonContext(ctx context.Context, pokemonName string) (*PokemonResult, error) {
+	return Fetch[PokemonResult](ctx, c, "pokemon/"+pokemonName)
 }
 
 type LocationResult struct {
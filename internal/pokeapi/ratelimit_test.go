@@ -0,0 +1,64 @@
+package pokeapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithRateLimit(100, 2),
+	)
+
+	const requests = 7
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Distinct names so the requests are not coalesced
+			if _, err := client.GetPokemon(fmt.Sprintf("pokemon-%d", i)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// The burst covers 2 requests, the remaining 5 are spaced 10ms apart
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("expected requests to be limited, took %v", elapsed)
+	}
+
+	stats := client.LimiterStats()
+	if stats.Requests != requests {
+		t.Errorf("expected %d requests, got %d", requests, stats.Requests)
+	}
+	if stats.Delayed == 0 || stats.Delayed > requests-2 {
+		t.Errorf("expected up to %d delayed requests, got %d", requests-2, stats.Delayed)
+	}
+	if stats.TotalWait == 0 || stats.MaxWait == 0 {
+		t.Errorf("expected wait times to be recorded, got %+v", stats)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	client := pokeapi.NewClient(pokeapi.WithRateLimit(0, 0))
+
+	stats := client.LimiterStats()
+	if stats.Requests != 0 {
+		t.Errorf("expected no limiter stats, got %+v", stats)
+	}
+}
@@ -0,0 +1,161 @@
+package pokeapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+var testRetryPolicy = pokeapi.RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Jitter:         0.5,
+}
+
+func newFlakyServer(failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+
+	return server, &requests
+}
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	cases := []struct {
+		failures int32
+		status   int
+	}{
+		{failures: 1, status: http.StatusInternalServerError},
+		{failures: 3, status: http.StatusServiceUnavailable},
+		{failures: 2, status: http.StatusTooManyRequests},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			server, requests := newFlakyServer(c.failures, c.status)
+			defer server.Close()
+
+			client := pokeapi.NewClient(
+				pokeapi.WithBaseURL(server.URL),
+				pokeapi.WithRetryPolicy(testRetryPolicy),
+			)
+
+			result, err := client.GetPokemon("pikachu")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Name != "pikachu" {
+				t.Errorf("unexpected result: %s", result.Name)
+			}
+			if requests.Load() != c.failures+1 {
+				t.Errorf("expected %d requests, got %d", c.failures+1, requests.Load())
+			}
+		})
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, requests := newFlakyServer(10, http.StatusBadGateway)
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithRetryPolicy(testRetryPolicy),
+	)
+
+	_, err := client.GetPokemon("pikachu")
+	if !errors.Is(err, pokeapi.ErrServer) {
+		t.Errorf("expected ErrServer, got %v", err)
+	}
+	if requests.Load() != 4 {
+		t.Errorf("expected 4 requests, got %d", requests.Load())
+	}
+}
+
+func TestRetrySkipsNotFound(t *testing.T) {
+	server, requests := newFlakyServer(10, http.StatusNotFound)
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithRetryPolicy(testRetryPolicy),
+	)
+
+	_, err := client.GetPokemon("pikachu")
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestRetryConnectionReset(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijack failed: %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithRetryPolicy(testRetryPolicy),
+	)
+
+	_, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", requests.Load())
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	var firstAt, secondAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			firstAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		secondAt = time.Now()
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithRetryPolicy(testRetryPolicy),
+	)
+
+	_, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waited := secondAt.Sub(firstAt); waited < time.Second {
+		t.Errorf("expected to wait at least 1s, waited %v", waited)
+	}
+}
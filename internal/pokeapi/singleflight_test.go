@@ -0,0 +1,146 @@
+package pokeapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+type countingCache struct {
+	pokecache.Cache
+	adds atomic.Int32
+}
+
+func (c *countingCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	c.adds.Add(1)
+	c.Cache.AddWithTTL(key, val, ttl)
+}
+
+func TestConcurrentFetchesAreCoalesced(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	memory := pokecache.NewCache(time.Minute)
+	defer memory.Close()
+	cache := &countingCache{Cache: memory}
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(server.URL),
+		pokeapi.WithCache(cache),
+	)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := client.GetPokemon("pikachu")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if result.Name != "pikachu" {
+				t.Errorf("unexpected result: %s", result.Name)
+			}
+		}()
+	}
+
+	// Give every caller time to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+	if cache.adds.Load() != 1 {
+		t.Errorf("expected 1 cache.Add, got %d", cache.adds.Load())
+	}
+}
+
+func TestCoalescedFetchSurvivesFirstCallerCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := client.GetPokemonContext(firstCtx, "pikachu")
+		firstErr <- err
+	}()
+
+	// Let the first caller start the fetch before the second joins it
+	time.Sleep(20 * time.Millisecond)
+	secondResult := make(chan error)
+	go func() {
+		result, err := client.GetPokemon("pikachu")
+		if err == nil && result.Name != "pikachu" {
+			t.Errorf("unexpected result: %s", result.Name)
+		}
+		secondResult <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the first caller to be cancelled, got %v", err)
+	}
+
+	release <- struct{}{}
+	if err := <-secondResult; err != nil {
+		t.Errorf("expected the second caller to get the result, got %v", err)
+	}
+}
+
+func TestCoalescedFetchCancelledWhenEveryCallerLeaves(t *testing.T) {
+	requestDone := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(requestDone)
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetPokemonContext(ctx, "pikachu")
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	select {
+	case <-requestDone:
+	case <-time.After(time.Second):
+		t.Errorf("expected the shared request to be cancelled")
+	}
+}
@@ -0,0 +1,91 @@
+package pokeapi
+
+import (
+	"context"
+	"strings"
+)
+
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type SpeciesResult struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	BaseHappiness  int           `json:"base_happiness"`
+	CaptureRate    int           `json:"capture_rate"`
+	GrowthRate     NamedResource `json:"growth_rate"`
+	IsLegendary    bool          `json:"is_legendary"`
+	IsMythical     bool          `json:"is_mythical"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Genera []struct {
+		Genus    string        `json:"genus"`
+		Language NamedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   NamedResource `json:"language"`
+		Version    NamedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+}
+
+func (c *Client) GetSpecies(speciesName string) (*SpeciesResult, error) {
+	return c.GetSpeciesContext(context.Background(), speciesName)
+}
+
+func (c *Client) GetSpeciesContext(ctx context.Context, speciesName string) (*SpeciesResult, error) {
+	return Fetch[SpeciesResult](ctx, c, "pokemon-species/"+speciesName)
+}
+
+func (c *Client) GetPokemonSpecies(pokemon *PokemonResult) (*SpeciesResult, error) {
+	return c.GetPokemonSpeciesContext(context.Background(), pokemon)
+}
+
+// GetPokemonSpeciesContext follows the species link of a pokemon, which
+// isn't always named like the pokemon itself (e.g. forms)
+func (c *Client) GetPokemonSpeciesContext(ctx context.Context, pokemon *PokemonResult) (*SpeciesResult, error) {
+	if pokemon.Species.URL == "" {
+		return c.GetSpeciesContext(ctx, pokemon.Name)
+	}
+
+	return FetchURL[SpeciesResult](ctx, c, pokemon.Species.URL)
+}
+
+func (s *SpeciesResult) Genus(language string) string {
+	for _, genus := range s.Genera {
+		if genus.Language.Name == language {
+			return genus.Genus
+		}
+	}
+	return ""
+}
+
+// FlavorText returns the entry for language from the given game version.
+// If version is empty or has no entry, the most recent entry is used.
+func (s *SpeciesResult) FlavorText(language string, version string) (string, bool) {
+	found := ""
+	for _, entry := range s.FlavorTextEntries {
+		if entry.Language.Name != language {
+			continue
+		}
+
+		found = entry.FlavorText
+		if version != "" && entry.Version.Name == version {
+			break
+		}
+	}
+
+	if found == "" {
+		return "", false
+	}
+	return cleanText(found), true
+}
+
+// cleanText collapses whitespace. Flavor and effect text keep the line
+// breaks and page breaks of the games' text boxes.
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,82 @@
+package pokeapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+const speciesJson = `{
+	"name": "pikachu",
+	"capture_rate": 190,
+	"base_happiness": 50,
+	"is_legendary": false,
+	"growth_rate": {"name": "medium"},
+	"evolution_chain": {"url": "https://pokeapi.co/api/v2/evolution-chain/10/"},
+	"genera": [
+		{"genus": "ねずみポケモン", "language": {"name": "ja"}},
+		{"genus": "Mouse Pokémon", "language": {"name": "en"}}
+	],
+	"flavor_text_entries": [
+		{"flavor_text": "When several of\nthese POKéMON\fgather, their\nelectricity could\nbuild and cause\nlightning storms.", "language": {"name": "en"}, "version": {"name": "red"}},
+		{"flavor_text": "Il se peut que plusieurs Pikachu", "language": {"name": "fr"}, "version": {"name": "x"}},
+		{"flavor_text": "It keeps its tail\nraised to monitor\nits surroundings.", "language": {"name": "en"}, "version": {"name": "yellow"}}
+	]
+}`
+
+func TestGetPokemonSpecies(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(speciesJson))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	var pokemon pokeapi.PokemonResult
+	pokemon.Name = "pikachu-rock-star"
+	pokemon.Species.URL = server.URL + "/pokemon-species/25/"
+
+	species, err := client.GetPokemonSpeciesContext(context.Background(), &pokemon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/pokemon-species/25/" {
+		t.Errorf("expected species URL to be followed, got %s", gotPath)
+	}
+	if species.CaptureRate != 190 || species.GrowthRate.Name != "medium" {
+		t.Errorf("unexpected species: %+v", species)
+	}
+	if species.EvolutionChain.URL != "https://pokeapi.co/api/v2/evolution-chain/10/" {
+		t.Errorf("unexpected evolution chain URL %s", species.EvolutionChain.URL)
+	}
+
+	if genus := species.Genus("en"); genus != "Mouse Pokémon" {
+		t.Errorf("expected Mouse Pokémon, got %s", genus)
+	}
+
+	cases := []struct {
+		language string
+		version  string
+		expected string
+	}{
+		{language: "en", version: "red", expected: "When several of these POKéMON gather, their electricity could build and cause lightning storms."},
+		{language: "en", version: "", expected: "It keeps its tail raised to monitor its surroundings."},
+		{language: "en", version: "scarlet", expected: "It keeps its tail raised to monitor its surroundings."},
+		{language: "fr", version: "red", expected: "Il se peut que plusieurs Pikachu"},
+	}
+	for _, c := range cases {
+		text, ok := species.FlavorText(c.language, c.version)
+		if !ok || text != c.expected {
+			t.Errorf("%s/%s: expected %q, got %q", c.language, c.version, c.expected, text)
+		}
+	}
+
+	if _, ok := species.FlavorText("de", ""); ok {
+		t.Errorf("expected no flavor text in de")
+	}
+}
@@ -0,0 +1,40 @@
+package pokeapi
+
+import "context"
+
+type TypeResult struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	DamageRelations DamageRelations `json:"damage_relations"`
+	Pokemon         []struct {
+		Slot    int           `json:"slot"`
+		Pokemon NamedResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+// DamageRelations lists the types this type deals extra, reduced or no
+// damage to, and takes extra, reduced or no damage from
+type DamageRelations struct {
+	DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+	HalfDamageTo     []NamedResource `json:"half_damage_to"`
+	NoDamageTo       []NamedResource `json:"no_damage_to"`
+	DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+	HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+	NoDamageFrom     []NamedResource `json:"no_damage_from"`
+}
+
+func (c *Client) GetType(typeName string) (*TypeResult, error) {
+	return c.GetTypeContext(context.Background(), typeName)
+}
+
+func (c *Client) GetTypeContext(ctx context.Context, typeName string) (*TypeResult, error) {
+	return Fetch[TypeResult](ctx, c, "type/"+typeName)
+}
+
+func (t *TypeResult) PokemonNames() []string {
+	names := []string{}
+	for _, pokemon := range t.Pokemon {
+		names = append(names, pokemon.Pokemon.Name)
+	}
+	return names
+}
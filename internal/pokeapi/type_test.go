@@ -0,0 +1,60 @@
+package pokeapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/PFrek/pokedexgo/internal/pokeapi"
+)
+
+const typeJson = `{
+	"id": 10,
+	"name": "fire",
+	"damage_relations": {
+		"double_damage_to": [{"name": "grass"}, {"name": "ice"}],
+		"half_damage_to": [{"name": "water"}],
+		"no_damage_to": [],
+		"double_damage_from": [{"name": "water"}, {"name": "ground"}],
+		"half_damage_from": [{"name": "fire"}],
+		"no_damage_from": []
+	},
+	"pokemon": [
+		{"slot": 1, "pokemon": {"name": "charmander"}},
+		{"slot": 2, "pokemon": {"name": "growlithe"}}
+	]
+}`
+
+func TestGetType(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(typeJson))
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+
+	result, err := client.GetTypeContext(context.Background(), "fire")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/type/fire" {
+		t.Errorf("expected /type/fire, got %s", gotPath)
+	}
+
+	relations := result.DamageRelations
+	if len(relations.DoubleDamageTo) != 2 || relations.DoubleDamageTo[1].Name != "ice" {
+		t.Errorf("unexpected double damage to: %v", relations.DoubleDamageTo)
+	}
+	if len(relations.DoubleDamageFrom) != 2 || len(relations.HalfDamageFrom) != 1 || len(relations.NoDamageTo) != 0 {
+		t.Errorf("unexpected damage relations: %+v", relations)
+	}
+
+	names := result.PokemonNames()
+	if !slices.Equal(names, []string{"charmander", "growlithe"}) {
+		t.Errorf("unexpected pokemon %v", names)
+	}
+}
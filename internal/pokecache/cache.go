@@ -0,0 +1,190 @@
+package pokecache
+
+import "time"
+
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Add(key string, val []byte)
+	// A ttl of 0 uses the cache's default expiry
+	AddWithTTL(key string, val []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+// StaleCache is implemented by caches that can serve expired entries while
+// they are being refreshed
+type StaleCache interface {
+	GetStale(key string) ([]byte, bool)
+}
+
+// Validators are the HTTP response headers used to ask the server whether
+// a cached response is still up to date
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ValidatorCache is implemented by caches that can store validators
+// alongside an entry, and keep expired entries around to renew them
+type ValidatorCache interface {
+	AddWithValidators(key string, val []byte, ttl time.Duration, validators Validators)
+	GetValidators(key string) (Validators, bool)
+	GetExpired(key string) ([]byte, bool)
+}
+
+// ClearableCache is implemented by caches that can remove every entry
+type ClearableCache interface {
+	Clear()
+}
+
+// NoopCache never stores anything, every Get is a miss
+type NoopCache struct{}
+
+func (NoopCache) Get(key string) ([]byte, bool) {
+	return nil, false
+}
+
+func (NoopCache) Add(key string, val []byte) {}
+
+func (NoopCache) AddWithTTL(key string, val []byte, ttl time.Duration) {}
+
+func (NoopCache) Delete(key string) {}
+
+// LayeredCache checks each layer in order, so faster caches should come
+// first. A hit in a lower layer is copied into the layers above it.
+type LayeredCache struct {
+	layers []Cache
+}
+
+func NewLayeredCache(layers ...Cache) *LayeredCache {
+	return &LayeredCache{
+		layers: layers,
+	}
+}
+
+func (l *LayeredCache) Get(key string) ([]byte, bool) {
+	for i, layer := range l.layers {
+		val, ok := layer.Get(key)
+		if !ok {
+			continue
+		}
+
+		// The upper layers don't know the entry's remaining TTL, so they
+		// fall back to their own default
+		for _, upper := range l.layers[:i] {
+			upper.Add(key, val)
+		}
+		return val, true
+	}
+
+	return nil, false
+}
+
+func (l *LayeredCache) Add(key string, val []byte) {
+	for _, layer := range l.layers {
+		layer.Add(key, val)
+	}
+}
+
+func (l *LayeredCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	for _, layer := range l.layers {
+		layer.AddWithTTL(key, val, ttl)
+	}
+}
+
+// AddWithValidators stores the validators in the layers that support them
+func (l *LayeredCache) AddWithValidators(key string, val []byte, ttl time.Duration, validators Validators) {
+	for _, layer := range l.layers {
+		if validatorLayer, ok := layer.(ValidatorCache); ok {
+			validatorLayer.AddWithValidators(key, val, ttl, validators)
+		} else {
+			layer.AddWithTTL(key, val, ttl)
+		}
+	}
+}
+
+func (l *LayeredCache) GetValidators(key string) (Validators, bool) {
+	for _, layer := range l.layers {
+		validatorLayer, ok := layer.(ValidatorCache)
+		if !ok {
+			continue
+		}
+
+		if validators, ok := validatorLayer.GetValidators(key); ok {
+			return validators, true
+		}
+	}
+
+	return Validators{}, false
+}
+
+// GetExpired returns the first expired entry found in a layer that
+// supports validators
+func (l *LayeredCache) GetExpired(key string) ([]byte, bool) {
+	for _, layer := range l.layers {
+		validatorLayer, ok := layer.(ValidatorCache)
+		if !ok {
+			continue
+		}
+
+		if val, ok := validatorLayer.GetExpired(key); ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetStale returns the first stale entry found in a layer that supports
+// stale entries
+func (l *LayeredCache) GetStale(key string) ([]byte, bool) {
+	for _, layer := range l.layers {
+		stale, ok := layer.(StaleCache)
+		if !ok {
+			continue
+		}
+
+		if val, ok := stale.GetStale(key); ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+func (l *LayeredCache) Delete(key string) {
+	for _, layer := range l.layers {
+		layer.Delete(key)
+	}
+}
+
+// Clear clears every layer that supports it
+func (l *LayeredCache) Clear() {
+	for _, layer := range l.layers {
+		if clearable, ok := layer.(ClearableCache); ok {
+			clearable.Clear()
+		}
+	}
+}
+
+var (
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*DiskCache)(nil)
+	_ Cache = (*LayeredCache)(nil)
+	_ Cache = (*RedisCache)(nil)
+	_ Cache = NoopCache{}
+
+	_ StaleCache = (*MemoryCache)(nil)
+	_ StaleCache = (*LayeredCache)(nil)
+
+	_ ValidatorCache = (*MemoryCache)(nil)
+	_ ValidatorCache = (*LayeredCache)(nil)
+
+	_ ClearableCache = (*MemoryCache)(nil)
+	_ ClearableCache = (*DiskCache)(nil)
+	_ ClearableCache = (*LayeredCache)(nil)
+	_ ClearableCache = (*RedisCache)(nil)
+)
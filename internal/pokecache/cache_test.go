@@ -0,0 +1,86 @@
+package pokecache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+func TestNoopCache(t *testing.T) {
+	cache := pokecache.NoopCache{}
+	cache.Add("https://example.com", []byte("testdata"))
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected to not find key")
+	}
+}
+
+func TestMemoryCacheDelete(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Delete("https://example.com")
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected key to be deleted")
+	}
+	if cache.Bytes() != 0 {
+		t.Errorf("expected 0 bytes, got %v", cache.Bytes())
+	}
+}
+
+func TestLayeredCache(t *testing.T) {
+	disk, err := pokecache.NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	memory := pokecache.NewCache(time.Minute)
+	cache := pokecache.NewLayeredCache(memory, disk)
+	cache.Add("https://example.com", []byte("testdata"))
+	memory.Close()
+
+	// A new memory layer starts empty, as after a restart
+	restarted := pokecache.NewCache(time.Minute)
+	defer restarted.Close()
+	cache = pokecache.NewLayeredCache(restarted, disk)
+
+	val, ok := cache.Get("https://example.com")
+	if !ok {
+		t.Fatalf("expected to find key on disk")
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected testdata, got %s", val)
+	}
+	if _, ok := restarted.Get("https://example.com"); !ok {
+		t.Errorf("expected entry to be copied into memory")
+	}
+
+	cache.Delete("https://example.com")
+	if _, ok := disk.Get("https://example.com"); ok {
+		t.Errorf("expected key to be deleted from every layer")
+	}
+}
+
+func TestLayeredCacheClear(t *testing.T) {
+	disk, err := pokecache.NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	memory := pokecache.NewCache(time.Minute)
+	defer memory.Close()
+	cache := pokecache.NewLayeredCache(memory, pokecache.NoopCache{}, disk)
+	cache.Add("https://example.com", []byte("testdata"))
+
+	cache.Clear()
+
+	if _, ok := memory.Get("https://example.com"); ok {
+		t.Errorf("expected memory layer to be cleared")
+	}
+	if _, ok := disk.Get("https://example.com"); ok {
+		t.Errorf("expected disk layer to be cleared")
+	}
+}
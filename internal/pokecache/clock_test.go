@@ -0,0 +1,94 @@
+package pokecache_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) pokecache.Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ticker := &fakeTicker{
+		interval: d,
+		next:     c.now.Add(d),
+		ch:       make(chan time.Time),
+		stopped:  make(chan struct{}),
+	}
+	c.tickers = append(c.tickers, ticker)
+	return ticker
+}
+
+// Advance moves the clock forward by d, delivering every tick that falls in
+// between. Ticks are sent on unbuffered channels, and the last one is sent
+// twice, so once Advance returns the reaper has finished handling them.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	tickers := append([]*fakeTicker{}, c.tickers...)
+	c.mu.Unlock()
+
+	for _, ticker := range tickers {
+		var last time.Time
+		for !ticker.next.After(end) {
+			c.set(ticker.next)
+			last = ticker.next
+			ticker.send(last)
+			ticker.next = ticker.next.Add(ticker.interval)
+		}
+		if !last.IsZero() {
+			ticker.send(last)
+		}
+	}
+
+	c.set(end)
+}
+
+func (c *fakeClock) set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+type fakeTicker struct {
+	interval time.Duration
+	next     time.Time
+	ch       chan time.Time
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopped)
+	})
+}
+
+func (t *fakeTicker) send(now time.Time) {
+	select {
+	case t.ch <- now:
+	case <-t.stopped:
+	}
+}
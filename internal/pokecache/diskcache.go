@@ -0,0 +1,286 @@
+package pokecache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const diskCacheExt = ".cache"
+
+// DiskCache stores each entry in its own file, named after the hash of its
+// key. The file holds the key on the first line, the expiry as unix
+// nanoseconds on the second (0 for the cache's ttl), followed by the value.
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	mu       sync.Mutex
+
+	done      chan struct{}
+	closeOnce sync.Once
+	cleaners  sync.WaitGroup
+}
+
+func NewDiskCache(dir string, ttl time.Duration, maxBytes int64) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("Disk cache error: %w", err)
+	}
+
+	cache := DiskCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		done:     make(chan struct{}),
+	}
+
+	err = cache.Cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+// StartCleanup runs Cleanup every interval until Close is called, so the
+// cache stays within maxBytes during long sessions
+func (d *DiskCache) StartCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	d.cleaners.Add(1)
+	go func() {
+		defer d.cleaners.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.done:
+				return
+			case <-ticker.C:
+				// Best effort, the next tick tries again
+				d.Cleanup()
+			}
+		}
+	}()
+}
+
+// Close stops the cleanup goroutine. It is safe to call more than once.
+func (d *DiskCache) Close() {
+	d.closeOnce.Do(func() {
+		close(d.done)
+	})
+	d.cleaners.Wait()
+}
+
+func (d *DiskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(hash[:])+diskCacheExt)
+}
+
+// Add is best effort, a failed write only costs a refetch. Use Write to
+// find out whether the entry was stored.
+func (d *DiskCache) Add(key string, val []byte) {
+	d.Write(key, val, 0)
+}
+
+func (d *DiskCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	d.Write(key, val, ttl)
+}
+
+func (d *DiskCache) Write(key string, val []byte, ttl time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("Disk cache error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	header := fmt.Sprintf("%s\n%d\n", key, expiresAt)
+	_, err = tmp.Write(append([]byte(header), val...))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Disk cache error: %w", err)
+	}
+
+	// Renaming makes the write atomic, readers never see a partial file
+	err = os.Rename(tmp.Name(), d.path(key))
+	if err != nil {
+		return fmt.Errorf("Disk cache error: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	storedKey, rest, _ := bytes.Cut(data, []byte("\n"))
+	expiry, val, ok := bytes.Cut(rest, []byte("\n"))
+	if !ok || string(storedKey) != key {
+		return nil, false
+	}
+
+	if d.expired(info, parseDiskExpiry(expiry), time.Now()) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return val, true
+}
+
+func (d *DiskCache) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	os.Remove(d.path(key))
+}
+
+// Clear removes every entry. Like Add it is best effort, an entry that
+// can't be removed is left for Cleanup.
+func (d *DiskCache) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != diskCacheExt {
+			continue
+		}
+		os.Remove(filepath.Join(d.dir, file.Name()))
+	}
+}
+
+func parseDiskExpiry(expiry []byte) time.Time {
+	nanos, err := strconv.ParseInt(string(expiry), 10, 64)
+	if err != nil || nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
+
+// readDiskExpiry reads the expiry from a file's header without reading the
+// whole value
+func readDiskExpiry(path string) (time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if _, err := reader.ReadString('\n'); err != nil {
+		return time.Time{}, err
+	}
+	expiry, err := reader.ReadString('\n')
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseDiskExpiry([]byte(strings.TrimSuffix(expiry, "\n"))), nil
+}
+
+func (d *DiskCache) expired(info fs.FileInfo, expiresAt time.Time, now time.Time) bool {
+	if !expiresAt.IsZero() {
+		return !now.Before(expiresAt)
+	}
+	return d.ttl > 0 && now.Sub(info.ModTime()) >= d.ttl
+}
+
+// Cleanup removes expired entries, then the oldest entries until the cache
+// fits in maxBytes
+func (d *DiskCache) Cleanup() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("Disk cache error: %w", err)
+	}
+
+	now := time.Now()
+	infos := []fs.FileInfo{}
+	var total int64
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != diskCacheExt {
+			continue
+		}
+
+		info, err := file.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Disk cache error: %w", err)
+		}
+
+		path := filepath.Join(d.dir, info.Name())
+		expiresAt, err := readDiskExpiry(path)
+		if err != nil || d.expired(info, expiresAt, now) {
+			os.Remove(path)
+			continue
+		}
+
+		infos = append(infos, info)
+		total += info.Size()
+	}
+
+	if d.maxBytes <= 0 || total <= d.maxBytes {
+		return nil
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+
+	for _, info := range infos {
+		if total <= d.maxBytes {
+			break
+		}
+
+		err := os.Remove(filepath.Join(d.dir, info.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Disk cache error: %w", err)
+		}
+		total -= info.Size()
+	}
+
+	return nil
+}
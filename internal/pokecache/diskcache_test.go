@@ -0,0 +1,172 @@
+package pokecache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+func ageFiles(t *testing.T, dir string, age time.Duration) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.cache"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	old := time.Now().Add(-age)
+	for _, file := range files {
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestDiskCachePersists(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := disk.Write("https://example.com", []byte("testdata"), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	val, ok := reopened.Get("https://example.com")
+	if !ok {
+		t.Fatalf("expected to find key")
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected testdata, got %s", val)
+	}
+
+	reopened.Delete("https://example.com")
+	if _, ok := reopened.Get("https://example.com"); ok {
+		t.Errorf("expected key to be deleted")
+	}
+}
+
+func TestDiskCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	disk.Add("https://example.com", []byte("testdata"))
+
+	ageFiles(t, dir, 2*time.Hour)
+
+	if _, ok := disk.Get("https://example.com"); ok {
+		t.Errorf("expected key to be expired")
+	}
+}
+
+func TestDiskCacheCleanup(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	disk.Add("old", []byte("0123456789012345678901234567890"))
+	ageFiles(t, dir, time.Minute)
+	disk.Add("new", []byte("0123456789012345678901234567890"))
+
+	if err := disk.Cleanup(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := disk.Get("old"); ok {
+		t.Errorf("expected oldest entry to be removed")
+	}
+	if _, ok := disk.Get("new"); !ok {
+		t.Errorf("expected newest entry to be kept")
+	}
+}
+
+func TestDiskCacheStartCleanup(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	disk.StartCleanup(time.Millisecond)
+	defer disk.Close()
+
+	disk.Add("old", []byte("0123456789012345678901234567890"))
+	ageFiles(t, dir, time.Minute)
+	disk.Add("new", []byte("0123456789012345678901234567890"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := disk.Get("old"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected oldest entry to be removed by a cleanup tick")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, ok := disk.Get("new"); !ok {
+		t.Errorf("expected newest entry to be kept")
+	}
+
+	disk.Close()
+	disk.Close()
+}
+
+func TestDiskCacheClear(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	disk.Add("https://example.com/1", []byte("testdata"))
+	disk.Add("https://example.com/2", []byte("testdata"))
+	disk.Clear()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.cache"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("expected every entry to be removed, got %v", files)
+	}
+}
+
+func TestDiskCachePerEntryTTL(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	disk.AddWithTTL("short", []byte("testdata"), time.Millisecond)
+	disk.AddWithTTL("long", []byte("testdata"), 48*time.Hour)
+
+	// Older than the cache's ttl, but not the long entry's own ttl
+	ageFiles(t, dir, 2*time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := disk.Get("short"); ok {
+		t.Errorf("expected short entry to be expired")
+	}
+	if err := disk.Cleanup(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := disk.Get("long"); !ok {
+		t.Errorf("expected long entry to outlive the cache's ttl")
+	}
+}
@@ -0,0 +1,90 @@
+package pokecache
+
+import "sync"
+
+type EvictionReason int
+
+const (
+	// The entry outlived its TTL, and stale window if any
+	EvictionExpired EvictionReason = iota
+	// The entry was the least recently used when the cache went over a
+	// size bound, or was replaced by a value too large to store
+	EvictionCapacity
+	// The entry was removed by Delete or Clear
+	EvictionDeleted
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionExpired:
+		return "expired"
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionDeleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
+type AddHook func(key string, val []byte)
+type EvictHook func(key string, val []byte, reason EvictionReason)
+
+// Hooks run synchronously in the goroutine that caused the event, after the
+// cache's locks are released, so they may call back into the cache. Expiry
+// hooks run in the reaper goroutine.
+type hooks struct {
+	mu      sync.RWMutex
+	onAdd   []AddHook
+	onEvict []EvictHook
+}
+
+// OnAdd registers a hook called after an entry is stored
+func (c *MemoryCache) OnAdd(hook AddHook) {
+	c.hooks.mu.Lock()
+	defer c.hooks.mu.Unlock()
+
+	c.hooks.onAdd = append(c.hooks.onAdd, hook)
+}
+
+// OnEvict registers a hook called after an entry is removed. val is the
+// uncompressed value of the removed entry.
+func (c *MemoryCache) OnEvict(hook EvictHook) {
+	c.hooks.mu.Lock()
+	defer c.hooks.mu.Unlock()
+
+	c.hooks.onEvict = append(c.hooks.onEvict, hook)
+}
+
+// Hooks are appended but never modified, so the returned slices can be
+// used without holding the lock
+func (h *hooks) snapshot() ([]AddHook, []EvictHook) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.onAdd, h.onEvict
+}
+
+func (c *MemoryCache) fireAdd(key string, val []byte) {
+	onAdd, _ := c.hooks.snapshot()
+	for _, hook := range onAdd {
+		hook(key, val)
+	}
+}
+
+func (c *MemoryCache) fireEvict(entries []*cacheEntry, reason EvictionReason) {
+	_, onEvict := c.hooks.snapshot()
+	if len(onEvict) == 0 {
+		return
+	}
+
+	for _, entry := range entries {
+		val, ok := entry.value()
+		if !ok {
+			continue
+		}
+
+		for _, hook := range onEvict {
+			hook(entry.key, val, reason)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package pokecache_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+type eviction struct {
+	key    string
+	val    string
+	reason pokecache.EvictionReason
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	added     []string
+	evictions []eviction
+}
+
+func (r *recorder) onAdd(key string, val []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.added = append(r.added, key)
+}
+
+func (r *recorder) onEvict(key string, val []byte, reason pokecache.EvictionReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictions = append(r.evictions, eviction{key: key, val: string(val), reason: reason})
+}
+
+func (r *recorder) snapshot() ([]string, []eviction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.added...), append([]eviction{}, r.evictions...)
+}
+
+func TestHooks(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithMaxEntries(2),
+		pokecache.WithMaxBytes(64),
+		pokecache.WithCompression(1),
+	)
+	defer cache.Close()
+
+	r := &recorder{}
+	cache.OnAdd(r.onAdd)
+	cache.OnEvict(r.onEvict)
+
+	cache.Add("a", []byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+	cache.Add("b", []byte("2"))
+	cache.Add("c", []byte("3"))
+	cache.Add("d", []byte("4"))
+	// Too large to store even compressed, so the value it replaces is
+	// dropped
+	large := make([]byte, 128)
+	rand.New(rand.NewSource(1)).Read(large)
+	cache.Add("d", large)
+	cache.Delete("b")
+	cache.Delete("missing")
+	cache.Clear()
+
+	added, evictions := r.snapshot()
+	if len(added) != 4 {
+		t.Errorf("expected 4 adds, got %v", added)
+	}
+
+	expected := []eviction{
+		{key: "a", val: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", reason: pokecache.EvictionCapacity},
+		{key: "b", val: "2", reason: pokecache.EvictionCapacity},
+		{key: "d", val: "4", reason: pokecache.EvictionCapacity},
+		{key: "c", val: "3", reason: pokecache.EvictionDeleted},
+	}
+	if len(evictions) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, evictions)
+	}
+	for i := range expected {
+		if evictions[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], evictions[i])
+		}
+	}
+}
+
+func TestHooksExpiry(t *testing.T) {
+	const interval = 5 * time.Millisecond
+	clock := newFakeClock()
+	cache := pokecache.NewCache(interval, pokecache.WithClock(clock))
+	defer cache.Close()
+
+	r := &recorder{}
+	cache.OnEvict(r.onEvict)
+	cache.Add("https://example.com", []byte("testdata"))
+
+	clock.Advance(interval)
+
+	_, evictions := r.snapshot()
+	if len(evictions) != 1 || evictions[0].reason != pokecache.EvictionExpired {
+		t.Errorf("expected one expiry eviction, got %v", evictions)
+	}
+}
@@ -1,62 +1,400 @@
 package pokecache
 
 import (
+	"context"
+	"hash/fnv"
 	"sync"
 	"time"
 )
 
 type cacheEntry struct {
-	createdAt time.Time
-	val       []byte
+	key        string
+	createdAt  time.Time
+	expiresAt  time.Time
+	val        []byte
+	compressed bool
+	validators Validators
 }
 
-type Cache struct {
-	entries map[string]cacheEntry
-	mu      sync.Mutex
+// Entries are never modified once added, so value is safe to call without
+// holding the shard's lock
+func (e *cacheEntry) value() ([]byte, bool) {
+	if !e.compressed {
+		return e.val, true
+	}
+
+	val, err := decompress(e.val)
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+func (e *cacheEntry) size() int {
+	return len(e.key) + len(e.val) + len(e.validators.ETag) + len(e.validators.LastModified)
+}
+
+type MemoryCache struct {
+	shards []*shard
+
+	interval         time.Duration
+	staleWindow      time.Duration
+	revalidateWindow time.Duration
+	shardCount       int
+	maxEntries       int
+	maxBytes         int
+	// Values of at least this many bytes are stored gzipped, 0 disables
+	compressThreshold int
+
+	usage usage
+	// Adds to a bounded cache are serialized, so room is made before an
+	// entry is stored and the bounds are never exceeded
+	boundMu sync.Mutex
+
+	hooks hooks
+	clock Clock
+
+	ctx       context.Context
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Bytes     int
+}
+
+type EntryInfo struct {
+	Key       string
+	Size      int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type Option func(*MemoryCache)
+
+// WithContext closes the cache once ctx is done
+func WithContext(ctx context.Context) Option {
+	return func(c *MemoryCache) {
+		c.ctx = ctx
+	}
+}
+
+// WithMaxEntries evicts the least recently used entries once the cache
+// holds more than maxEntries
+func WithMaxEntries(maxEntries int) Option {
+	return func(c *MemoryCache) {
+		c.maxEntries = maxEntries
+	}
 }
 
-func NewCache(interval time.Duration) *Cache {
-	cache := Cache{
-		entries: make(map[string]cacheEntry),
+// WithMaxBytes evicts the least recently used entries once the keys and
+// values stored add up to more than maxBytes
+func WithMaxBytes(maxBytes int) Option {
+	return func(c *MemoryCache) {
+		c.maxBytes = maxBytes
 	}
-	cache.reapLoop(interval)
+}
+
+// WithStaleWhileRevalidate keeps expired entries around for window, so
+// GetStale can serve them while a fresh copy is fetched
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(c *MemoryCache) {
+		c.staleWindow = window
+	}
+}
+
+// WithRevalidateWindow keeps expired entries that have validators around
+// for window, so they can be renewed with a conditional request instead of
+// downloaded again. It defaults to the cache's interval.
+func WithRevalidateWindow(window time.Duration) Option {
+	return func(c *MemoryCache) {
+		c.revalidateWindow = window
+	}
+}
+
+// WithCompression gzips values of at least threshold bytes. Values that
+// don't shrink are stored as they are.
+func WithCompression(threshold int) Option {
+	return func(c *MemoryCache) {
+		c.compressThreshold = threshold
+	}
+}
+
+// WithClock replaces the system clock, used for expiry and reaping
+func WithClock(clock Clock) Option {
+	return func(c *MemoryCache) {
+		c.clock = clock
+	}
+}
+
+// WithShards splits the cache into n shards, each with its own lock, to
+// reduce contention between goroutines. The size bounds still apply to the
+// whole cache, so adds to a bounded cache share one lock, and eviction is
+// least recently used within a shard.
+func WithShards(n int) Option {
+	return func(c *MemoryCache) {
+		c.shardCount = n
+	}
+}
+
+// NewCache creates a cache whose entries expire after interval, unless
+// added with their own TTL
+func NewCache(interval time.Duration, options ...Option) *MemoryCache {
+	cache := MemoryCache{
+		interval:         interval,
+		revalidateWindow: interval,
+		shardCount:       1,
+		clock:            realClock{},
+		ctx:              context.Background(),
+		done:             make(chan struct{}),
+		stopped:          make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(&cache)
+	}
+
+	cache.shardCount = max(cache.shardCount, 1)
+	for i := 0; i < cache.shardCount; i++ {
+		cache.shards = append(cache.shards, newShard(
+			&cache.usage,
+			cache.staleWindow,
+			cache.revalidateWindow,
+		))
+	}
+
+	cache.reapLoop()
 
 	return &cache
 }
 
-func (c *Cache) Add(key string, val []byte) {
-	c.mu.Lock()
-	c.entries[key] = cacheEntry{
-		createdAt: time.Now(),
-		val:       val,
+// Close stops the reaper goroutine. It is safe to call more than once.
+func (c *MemoryCache) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.stopped
+}
+
+func (c *MemoryCache) Add(key string, val []byte) {
+	c.AddWithTTL(key, val, 0)
+}
+
+// AddWithTTL adds an entry that expires after ttl, or after the cache's
+// interval if ttl is 0
+func (c *MemoryCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	c.AddWithValidators(key, val, ttl, Validators{})
+}
+
+func (c *MemoryCache) AddWithValidators(key string, val []byte, ttl time.Duration, validators Validators) {
+	stored := val
+	compressed := false
+	if c.compressThreshold > 0 && len(val) >= c.compressThreshold {
+		if gzipped, err := compress(val); err == nil && len(gzipped) < len(val) {
+			stored = gzipped
+			compressed = true
+		}
+	}
+
+	if ttl <= 0 {
+		ttl = c.interval
+	}
+
+	now := c.clock.Now()
+	entry := &cacheEntry{
+		key:        key,
+		createdAt:  now,
+		expiresAt:  now.Add(ttl),
+		val:        stored,
+		compressed: compressed,
+		validators: validators,
+	}
+	var evicted []*cacheEntry
+	if c.bounded() {
+		c.boundMu.Lock()
+		evicted = c.makeRoom(entry)
+	}
+	added, dropped := c.shardFor(key).add(entry, c.maxBytes)
+	if c.bounded() {
+		c.boundMu.Unlock()
+	}
+
+	if added {
+		c.fireAdd(key, val)
+	}
+	if dropped != nil {
+		evicted = append(evicted, dropped)
+	}
+	c.fireEvict(evicted, EvictionCapacity)
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	entry, ok := c.shardFor(key).getFresh(key, c.clock.Now())
+	if !ok {
+		return nil, false
 	}
-	c.mu.Unlock()
+
+	return entry.value()
+}
+
+// GetStale returns an entry that has expired but is still within the stale
+// window. Entries that are still fresh are left to Get.
+func (c *MemoryCache) GetStale(key string) ([]byte, bool) {
+	entry, ok := c.shardFor(key).getStale(key, c.clock.Now())
+	if !ok {
+		return nil, false
+	}
+
+	return entry.value()
 }
 
-func (c *Cache) Get(key string) ([]byte, bool) {
-	c.mu.Lock()
-	entry, ok := c.entries[key]
-	c.mu.Unlock()
+// GetExpired returns an entry that has expired but still has validators,
+// so it can be renewed with a conditional request
+func (c *MemoryCache) GetExpired(key string) ([]byte, bool) {
+	entry, ok := c.shardFor(key).getExpired(key, c.clock.Now())
 	if !ok {
 		return nil, false
 	}
 
-	return entry.val, true
+	return entry.value()
 }
 
-func (c *Cache) reapLoop(interval time.Duration) {
-	ticker := time.NewTicker(interval)
+// GetValidators returns the validators of an entry, whether it is fresh or
+// stale
+func (c *MemoryCache) GetValidators(key string) (Validators, bool) {
+	return c.shardFor(key).validators(key)
+}
+
+func (c *MemoryCache) Delete(key string) {
+	entry, ok := c.shardFor(key).delete(key)
+	if ok {
+		c.fireEvict([]*cacheEntry{entry}, EvictionDeleted)
+	}
+}
+
+// Clear removes every entry, without counting them as evictions in Stats
+func (c *MemoryCache) Clear() {
+	for _, shard := range c.shards {
+		c.fireEvict(shard.clear(), EvictionDeleted)
+	}
+}
+
+func (c *MemoryCache) Stats() Stats {
+	var stats Stats
+	for _, shard := range c.shards {
+		shardStats := shard.stats()
+		stats.Hits += shardStats.Hits
+		stats.Misses += shardStats.Misses
+		stats.Evictions += shardStats.Evictions
+		stats.Entries += shardStats.Entries
+		stats.Bytes += shardStats.Bytes
+	}
+
+	return stats
+}
+
+// Entries lists the cached entries from most to least recently used, shard
+// by shard
+func (c *MemoryCache) Entries() []EntryInfo {
+	infos := []EntryInfo{}
+	for _, shard := range c.shards {
+		infos = append(infos, shard.entryInfos()...)
+	}
+
+	return infos
+}
+
+func (c *MemoryCache) Len() int {
+	return c.Stats().Entries
+}
+
+func (c *MemoryCache) Bytes() int {
+	return c.Stats().Bytes
+}
+
+func (c *MemoryCache) shardFor(key string) *shard {
+	return c.shards[c.shardIndex(key)]
+}
+
+func (c *MemoryCache) shardIndex(key string) int {
+	if len(c.shards) == 1 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(len(c.shards)))
+}
+
+func (c *MemoryCache) bounded() bool {
+	return c.maxEntries > 0 || c.maxBytes > 0
+}
+
+// makeRoom evicts entries until entry fits within the bounds, starting with
+// the least recently used entries of entry's shard and moving on to the
+// next shards once that one is empty. Must be called with c.boundMu held.
+func (c *MemoryCache) makeRoom(entry *cacheEntry) []*cacheEntry {
+	evicted := []*cacheEntry{}
+	if c.maxBytes > 0 && entry.size() > c.maxBytes {
+		return evicted
+	}
+
+	// Replacing an entry frees its space
+	replacedSize, replaced := c.shardFor(entry.key).size(entry.key)
+	start := c.shardIndex(entry.key)
+	for i := 0; i < len(c.shards); {
+		entries := c.usage.entries.Load()
+		if !replaced {
+			entries++
+		}
+		bytes := c.usage.bytes.Load() - int64(replacedSize) + int64(entry.size())
+
+		fits := (c.maxEntries <= 0 || entries <= int64(c.maxEntries)) &&
+			(c.maxBytes <= 0 || bytes <= int64(c.maxBytes))
+		if fits {
+			break
+		}
+
+		oldest, ok := c.shards[(start+i)%len(c.shards)].evictOldest(entry.key)
+		if !ok {
+			i++
+			continue
+		}
+		evicted = append(evicted, oldest)
+	}
+
+	return evicted
+}
+
+func (c *MemoryCache) reapLoop() {
+	ticker := c.clock.NewTicker(c.interval)
 
 	go func() {
-		t := <-ticker.C
-		c.mu.Lock()
-		for key, val := range c.entries {
-			difference := t.Sub(val.createdAt)
-			if difference >= interval {
-				delete(c.entries, key)
+		defer close(c.stopped)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-c.ctx.Done():
+				return
+			case t := <-ticker.C():
+				c.reap(t)
 			}
 		}
-
-		c.mu.Unlock()
 	}()
 }
+
+// Each shard is reaped under its own lock, so the rest of the cache stays
+// usable during a reap
+func (c *MemoryCache) reap(now time.Time) {
+	for _, shard := range c.shards {
+		c.fireEvict(shard.reap(now), EvictionExpired)
+	}
+}
@@ -1,8 +1,13 @@
 package pokecache_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/PFrek/pokedexgo/internal/pokecache"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -26,6 +31,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := pokecache.NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -42,8 +48,9 @@ func TestAddGet(t *testing.T) {
 
 func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
-	const waitTime = baseTime + 5*time.Millisecond
-	cache := pokecache.NewCache(baseTime)
+	clock := newFakeClock()
+	cache := pokecache.NewCache(baseTime, pokecache.WithClock(clock))
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -52,11 +59,500 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 
-	time.Sleep(waitTime)
+	clock.Advance(baseTime - time.Nanosecond)
+
+	_, ok = cache.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key until the interval has passed")
+		return
+	}
+
+	clock.Advance(time.Nanosecond)
 
 	_, ok = cache.Get("https://example.com")
 	if ok {
 		t.Errorf("expected to not find key")
 		return
 	}
+	if cache.Len() != 0 {
+		t.Errorf("expected key to be reaped")
+	}
+}
+
+func TestReapLoopContinuous(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	clock := newFakeClock()
+	cache := pokecache.NewCache(interval, pokecache.WithClock(clock))
+	defer cache.Close()
+
+	for i := 0; i < 4; i++ {
+		key := fmt.Sprintf("https://example.com/%v", i)
+		cache.Add(key, []byte("testdata"))
+		clock.Advance(interval)
+
+		// Each tick reaps the entry added during the previous interval
+		if cache.Len() != 0 {
+			t.Errorf("expected %s to be reaped after tick %v", key, i+1)
+		}
+	}
+}
+
+// checkNoLeakedGoroutines polls, since a stopped goroutine may still be
+// exiting when Close returns
+func checkNoLeakedGoroutines(t *testing.T, before int) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		after := runtime.NumGoroutine()
+		if after <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("expected no leaked goroutines, had %v before and %v after", before, after)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		cache := pokecache.NewCache(time.Millisecond)
+		cache.Close()
+		cache.Close()
+	}
+
+	checkNoLeakedGoroutines(t, before)
+}
+
+func TestCloseWithContext(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache := pokecache.NewCache(time.Millisecond, pokecache.WithContext(ctx))
+	cancel()
+	cache.Close()
+
+	checkNoLeakedGoroutines(t, before)
+}
+
+func TestMaxEntries(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute, pokecache.WithMaxEntries(2))
+	defer cache.Close()
+
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+
+	// Using a makes b the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("expected to find key a")
+	}
+	cache.Add("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find key %s", key)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %v", cache.Len())
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute, pokecache.WithMaxBytes(20))
+	defer cache.Close()
+
+	cache.Add("a", []byte("123456789"))
+	cache.Add("b", []byte("123456789"))
+	cache.Add("c", []byte("123456789"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+	if cache.Bytes() != 20 {
+		t.Errorf("expected 20 bytes, got %v", cache.Bytes())
+	}
+
+	cache.Add("d", []byte("this value is too large to ever fit"))
+	if _, ok := cache.Get("d"); ok {
+		t.Errorf("expected oversized value to not be stored")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %v", cache.Len())
+	}
+}
+
+func TestBoundsConcurrent(t *testing.T) {
+	const maxEntries = 16
+	const maxBytes = 200
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithMaxEntries(maxEntries),
+		pokecache.WithMaxBytes(maxBytes),
+	)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key-%v-%v", g, i%40)
+				cache.Add(key, []byte("testdata"))
+				cache.Get(fmt.Sprintf("key-%v-%v", g, i%7))
+
+				if n := cache.Len(); n > maxEntries {
+					t.Errorf("expected at most %v entries, got %v", maxEntries, n)
+					return
+				}
+				if b := cache.Bytes(); b > maxBytes {
+					t.Errorf("expected at most %v bytes, got %v", maxBytes, b)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestStats(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute, pokecache.WithMaxEntries(2))
+	defer cache.Close()
+
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	cache.Add("c", []byte("3"))
+	cache.Get("a")
+	cache.Get("b")
+	cache.Get("c")
+
+	stats := cache.Stats()
+	expected := pokecache.Stats{
+		Hits:      2,
+		Misses:    1,
+		Evictions: 1,
+		Entries:   2,
+		Bytes:     4,
+	}
+	if stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	entries := cache.Entries()
+	if len(entries) != 2 || entries[0].Key != "c" || entries[1].Key != "b" {
+		t.Errorf("expected entries c, b, got %+v", entries)
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 || cache.Bytes() != 0 {
+		t.Errorf("expected cache to be empty")
+	}
+	if cache.Stats().Evictions != 1 {
+		t.Errorf("expected clear to not count as evictions")
+	}
+}
+
+func TestAddWithTTL(t *testing.T) {
+	clock := newFakeClock()
+	cache := pokecache.NewCache(time.Minute, pokecache.WithClock(clock))
+	defer cache.Close()
+
+	cache.AddWithTTL("short", []byte("testdata"), time.Millisecond)
+	cache.AddWithTTL("long", []byte("testdata"), time.Hour)
+	clock.Advance(time.Millisecond)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Errorf("expected short entry to be expired")
+	}
+	if _, ok := cache.Get("long"); !ok {
+		t.Errorf("expected to find long entry")
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	const ttl = 5 * time.Millisecond
+	const window = time.Hour
+	clock := newFakeClock()
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithClock(clock),
+		pokecache.WithStaleWhileRevalidate(window),
+	)
+	defer cache.Close()
+
+	cache.AddWithTTL("https://example.com", []byte("testdata"), ttl)
+
+	if _, ok := cache.GetStale("https://example.com"); ok {
+		t.Errorf("expected fresh entry to not be stale")
+	}
+
+	clock.Advance(ttl)
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected entry to be expired")
+	}
+	val, ok := cache.GetStale("https://example.com")
+	if !ok {
+		t.Fatalf("expected to find stale entry")
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected testdata, got %s", val)
+	}
+
+	clock.Advance(window)
+
+	if _, ok := cache.GetStale("https://example.com"); ok {
+		t.Errorf("expected entry to be gone after the stale window")
+	}
+
+	// The next tick reaps it
+	clock.Advance(time.Minute)
+	if cache.Len() != 0 {
+		t.Errorf("expected entry to be reaped after the stale window")
+	}
+}
+
+func TestRevalidateWindow(t *testing.T) {
+	const ttl = 5 * time.Millisecond
+	const window = time.Hour
+	clock := newFakeClock()
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithClock(clock),
+		pokecache.WithRevalidateWindow(window),
+	)
+	defer cache.Close()
+
+	validators := pokecache.Validators{ETag: `"v1"`}
+	cache.AddWithValidators("https://example.com/validated", []byte("testdata"), ttl, validators)
+	cache.AddWithTTL("https://example.com/plain", []byte("testdata"), ttl)
+
+	if _, ok := cache.GetExpired("https://example.com/validated"); ok {
+		t.Errorf("expected fresh entry to not be expired")
+	}
+
+	// The first tick reaps the entry without validators only
+	clock.Advance(time.Minute)
+
+	if _, ok := cache.GetExpired("https://example.com/plain"); ok {
+		t.Errorf("expected entry without validators to be reaped")
+	}
+	val, ok := cache.GetExpired("https://example.com/validated")
+	if !ok || string(val) != "testdata" {
+		t.Fatalf("expected to find expired entry, got %s", val)
+	}
+	if _, ok := cache.GetStale("https://example.com/validated"); ok {
+		t.Errorf("expected expired entry to not be served as stale")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("expected GetExpired to not count as a lookup, got %+v", stats)
+	}
+
+	clock.Advance(window)
+	if cache.Len() != 0 {
+		t.Errorf("expected entry to be reaped after the revalidate window")
+	}
+}
+
+// pokemonBody builds a JSON body shaped like a /pokemon response, with the
+// long repetitive moves list that makes those responses large
+func pokemonBody() []byte {
+	var body strings.Builder
+	body.WriteString(`{"name": "pikachu", "moves": [`)
+	for i := 0; i < 300; i++ {
+		if i > 0 {
+			body.WriteString(",")
+		}
+		fmt.Fprintf(&body, `{"move": {"name": "move-%v", "url": "https://pokeapi.co/api/v2/move/%v/"}, "version_group_details": [{"level_learned_at": %v, "move_learn_method": {"name": "level-up", "url": "https://pokeapi.co/api/v2/move-learn-method/1/"}}]}`, i, i, i%50)
+	}
+	body.WriteString(`]}`)
+	return []byte(body.String())
+}
+
+func TestCompression(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute, pokecache.WithCompression(1024))
+	defer cache.Close()
+
+	body := pokemonBody()
+	cache.Add("https://example.com/pokemon/pikachu", body)
+	cache.Add("https://example.com/small", []byte("testdata"))
+
+	val, ok := cache.Get("https://example.com/pokemon/pikachu")
+	if !ok {
+		t.Fatalf("expected to find key")
+	}
+	if string(val) != string(body) {
+		t.Errorf("expected decompressed value to match")
+	}
+
+	val, ok = cache.Get("https://example.com/small")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected to find small value, got %s", val)
+	}
+
+	if cache.Bytes() >= len(body) {
+		t.Errorf("expected compressed size below %v bytes, got %v", len(body), cache.Bytes())
+	}
+}
+
+func benchmarkAddGet(b *testing.B, options ...pokecache.Option) {
+	cache := pokecache.NewCache(time.Minute, options...)
+	defer cache.Close()
+
+	body := pokemonBody()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		key := fmt.Sprintf("https://example.com/pokemon/%v", i%100)
+		cache.Add(key, body)
+		if _, ok := cache.Get(key); !ok {
+			b.Fatalf("expected to find key")
+		}
+	}
+
+	b.StopTimer()
+	b.ReportMetric(float64(cache.Bytes())/float64(cache.Len()), "bytes/entry")
+}
+
+func BenchmarkAddGet(b *testing.B) {
+	benchmarkAddGet(b)
+}
+
+func BenchmarkAddGetCompressed(b *testing.B) {
+	benchmarkAddGet(b, pokecache.WithCompression(1024))
+}
+
+func TestShards(t *testing.T) {
+	// Unbounded, so no key can be evicted between its Add and Get
+	cache := pokecache.NewCache(time.Minute, pokecache.WithShards(8))
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("https://example.com/%v/%v", g, i)
+				cache.Add(key, []byte("testdata"))
+				if _, ok := cache.Get(key); !ok {
+					t.Errorf("expected to find key %s", key)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	if stats.Entries != 8*200 {
+		t.Errorf("expected %v entries, got %v", 8*200, stats.Entries)
+	}
+	if stats.Hits != 8*200 {
+		t.Errorf("expected %v hits, got %v", 8*200, stats.Hits)
+	}
+	if len(cache.Entries()) != stats.Entries {
+		t.Errorf("expected entries from every shard")
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 {
+		t.Errorf("expected every shard to be cleared")
+	}
+}
+
+func TestShardsMaxEntries(t *testing.T) {
+	const maxEntries = 64
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithShards(8),
+		pokecache.WithMaxEntries(maxEntries),
+	)
+	defer cache.Close()
+
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("https://example.com/%v", i)
+		cache.Add(key, []byte("testdata"))
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find key %s", key)
+		}
+	}
+
+	if entries := cache.Len(); entries > maxEntries {
+		t.Errorf("expected at most %v entries, got %v", maxEntries, entries)
+	}
+}
+
+func TestShardsUnevenBounds(t *testing.T) {
+	const maxEntries = 4
+	cache := pokecache.NewCache(time.Minute,
+		pokecache.WithShards(16),
+		pokecache.WithMaxEntries(maxEntries),
+	)
+	defer cache.Close()
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("https://example.com/%v", i)
+		cache.Add(key, []byte("testdata"))
+		if entries := cache.Len(); entries > maxEntries {
+			t.Fatalf("expected at most %v entries, got %v", maxEntries, entries)
+		}
+	}
+	if _, ok := cache.Get("https://example.com/49"); !ok {
+		t.Errorf("expected the newest entry to be kept")
+	}
+
+	// A value that fits the cache is stored, however many shards there are
+	bytesCache := pokecache.NewCache(time.Minute,
+		pokecache.WithShards(16),
+		pokecache.WithMaxBytes(1000),
+	)
+	defer bytesCache.Close()
+
+	bytesCache.Add("https://example.com", make([]byte, 100))
+	if _, ok := bytesCache.Get("https://example.com"); !ok {
+		t.Errorf("expected a 100 byte value to fit in a 1000 byte cache")
+	}
+}
+
+func benchmarkParallel(b *testing.B, shards int) {
+	cache := pokecache.NewCache(time.Minute, pokecache.WithShards(shards))
+	defer cache.Close()
+
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("https://example.com/pokemon/%v", i)
+		cache.Add(keys[i], []byte("testdata"))
+	}
+
+	// Each goroutine starts at a different key so they don't move in
+	// lockstep through the same shard
+	var offset atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(offset.Add(97))
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			// Mostly reads, like the REPL hitting a warm cache
+			if i%10 == 0 {
+				cache.Add(key, []byte("testdata"))
+			} else {
+				cache.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkParallel1Shard(b *testing.B) {
+	benchmarkParallel(b, 1)
+}
+
+func BenchmarkParallel16Shards(b *testing.B) {
+	benchmarkParallel(b, 16)
 }
@@ -0,0 +1,251 @@
+package pokecache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redisKeyPrefix = "pokedexgo:"
+
+// After a failed dial, calls fail fast for a backoff that doubles between
+// these bounds, so an outage costs a miss instead of a dial timeout
+const redisMinBackoff = time.Second
+const redisMaxBackoff = time.Minute
+
+var errRedisUnavailable = errors.New("Redis error: server unavailable")
+
+// RedisCache talks the Redis protocol (RESP) to anything compatible with
+// GET, SET and DEL. Like DiskCache it is best effort: connection errors are
+// treated as misses and the connection is redialed on the next call, or
+// once a backoff has passed if dialing failed.
+type RedisCache struct {
+	addr    string
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu      sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func NewRedisCache(addr string, ttl time.Duration) *RedisCache {
+	return &RedisCache{
+		addr:    addr,
+		ttl:     ttl,
+		timeout: 2 * time.Second,
+	}
+}
+
+func (r *RedisCache) Get(key string) ([]byte, bool) {
+	val, err := r.do("GET", redisKeyPrefix+key)
+	if err != nil || val == nil {
+		return nil, false
+	}
+
+	return val, true
+}
+
+func (r *RedisCache) Add(key string, val []byte) {
+	r.AddWithTTL(key, val, 0)
+}
+
+func (r *RedisCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = r.ttl
+	}
+
+	args := []string{"SET", redisKeyPrefix + key, string(val)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+
+	r.do(args...)
+}
+
+func (r *RedisCache) Delete(key string) {
+	r.do("DEL", redisKeyPrefix+key)
+}
+
+func (r *RedisCache) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn = nil
+	r.reader = nil
+	return err
+}
+
+// do sends a command and returns its reply, nil for a null reply
+func (r *RedisCache) do(args ...string) ([]byte, error) {
+	var reply []byte
+	err := r.call(args, func(reader *bufio.Reader) error {
+		var err error
+		reply, err = readRedisReply(reader)
+		return err
+	})
+	return reply, err
+}
+
+// keys lists the keys matching pattern. KEYS blocks the server while it
+// runs, which is fine for an occasional clear but not for every lookup.
+func (r *RedisCache) keys(pattern string) ([]string, error) {
+	var keys []string
+	err := r.call([]string{"KEYS", pattern}, func(reader *bufio.Reader) error {
+		var err error
+		keys, err = readRedisArray(reader)
+		return err
+	})
+	return keys, err
+}
+
+// Clear removes every entry this cache added, leaving other keys alone
+func (r *RedisCache) Clear() {
+	keys, err := r.keys(redisKeyPrefix + "*")
+	if err != nil || len(keys) == 0 {
+		return
+	}
+
+	r.do(append([]string{"DEL"}, keys...)...)
+}
+
+func (r *RedisCache) call(args []string, read func(*bufio.Reader) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		if time.Now().Before(r.retryAt) {
+			return errRedisUnavailable
+		}
+
+		conn, err := net.DialTimeout("tcp", r.addr, r.timeout)
+		if err != nil {
+			r.backoff = min(max(2*r.backoff, redisMinBackoff), redisMaxBackoff)
+			r.retryAt = time.Now().Add(r.backoff)
+			return fmt.Errorf("Redis error: %w", err)
+		}
+		r.conn = conn
+		r.reader = bufio.NewReader(conn)
+		r.backoff = 0
+	}
+
+	r.conn.SetDeadline(time.Now().Add(r.timeout))
+
+	err := r.roundTrip(args, read)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection is in an unknown state, start over next time
+		r.conn.Close()
+		r.conn = nil
+		r.reader = nil
+	}
+
+	return err
+}
+
+func (r *RedisCache) roundTrip(args []string, read func(*bufio.Reader) error) error {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	_, err := io.WriteString(r.conn, command.String())
+	if err != nil {
+		return fmt.Errorf("Redis error: %w", err)
+	}
+
+	return read(r.reader)
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "Redis error: " + string(e)
+}
+
+func readRedisLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("Redis error: %w", err)
+	}
+
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func readRedisReply(reader *bufio.Reader) ([]byte, error) {
+	line, err := readRedisLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("Redis error: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("Redis error: invalid bulk length: %w", err)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, length+2)
+		_, err = io.ReadFull(reader, data)
+		if err != nil {
+			return nil, fmt.Errorf("Redis error: %w", err)
+		}
+		return data[:length], nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("Redis error: unsupported reply %q", line))
+}
+
+// readRedisArray reads an array of bulk strings, such as the reply to KEYS
+func readRedisArray(reader *bufio.Reader) ([]string, error) {
+	line, err := readRedisLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(line, "-") {
+		return nil, redisError(line[1:])
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, errors.New(fmt.Sprintf("Redis error: expected an array, got %q", line))
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("Redis error: invalid array length: %w", err)
+	}
+
+	items := []string{}
+	for i := 0; i < count; i++ {
+		item, err := readRedisReply(reader)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, string(item))
+	}
+
+	return items, nil
+}
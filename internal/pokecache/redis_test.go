@@ -0,0 +1,247 @@
+package pokecache_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PFrek/pokedexgo/internal/pokecache"
+)
+
+// fakeRedis is a minimal stand-in for a Redis server supporting GET, SET
+// and DEL
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	commands [][]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := &fakeRedis{
+		listener: listener,
+		data:     make(map[string]string),
+	}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			val, ok := f.data[args[1]]
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(val), val)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "DEL":
+			deleted := 0
+			for _, key := range args[1:] {
+				if _, ok := f.data[key]; ok {
+					deleted++
+				}
+				delete(f.data, key)
+			}
+			reply = fmt.Sprintf(":%d\r\n", deleted)
+		case "KEYS":
+			// Only prefix patterns are supported
+			prefix := strings.TrimSuffix(args[1], "*")
+			keys := []string{}
+			for key := range f.data {
+				if strings.HasPrefix(key, prefix) {
+					keys = append(keys, key)
+				}
+			}
+			reply = fmt.Sprintf("*%d\r\n", len(keys))
+			for _, key := range keys {
+				reply += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		io.WriteString(conn, reply)
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{}
+	for i := 0; i < count; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:length]))
+	}
+
+	return args, nil
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := pokecache.NewRedisCache(server.listener.Addr().String(), time.Minute)
+	defer cache.Close()
+
+	val := []byte("line one\r\nline two")
+	cache.Add("https://example.com", val)
+
+	got, ok := cache.Get("https://example.com")
+	if !ok {
+		t.Fatalf("expected to find key")
+	}
+	if string(got) != string(val) {
+		t.Errorf("expected %q, got %q", val, got)
+	}
+
+	server.mu.Lock()
+	set := server.commands[0]
+	server.mu.Unlock()
+	if len(set) != 5 || set[3] != "PX" || set[4] != "60000" {
+		t.Errorf("expected SET with a 60000ms expiry, got %v", set)
+	}
+
+	cache.Delete("https://example.com")
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected key to be deleted")
+	}
+}
+
+func TestRedisCacheClear(t *testing.T) {
+	server := newFakeRedis(t)
+	server.data["other:key"] = "untouched"
+	cache := pokecache.NewRedisCache(server.listener.Addr().String(), time.Minute)
+	defer cache.Close()
+
+	cache.Add("https://example.com/1", []byte("testdata"))
+	cache.Add("https://example.com/2", []byte("testdata"))
+	cache.Clear()
+
+	if _, ok := cache.Get("https://example.com/1"); ok {
+		t.Errorf("expected key to be cleared")
+	}
+	if _, ok := cache.Get("https://example.com/2"); ok {
+		t.Errorf("expected key to be cleared")
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.data["other:key"] != "untouched" {
+		t.Errorf("expected keys outside the prefix to be kept")
+	}
+}
+
+func TestRedisCacheUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	cache := pokecache.NewRedisCache(addr, time.Minute)
+	defer cache.Close()
+
+	cache.Add("https://example.com", []byte("testdata"))
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected a miss when the server is unavailable")
+	}
+}
+
+func TestRedisCacheBackoff(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	cache := pokecache.NewRedisCache(addr, time.Minute)
+	defer cache.Close()
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected a miss when the server is unavailable")
+	}
+
+	// The server comes back, but the cache waits out its backoff before
+	// dialing again
+	listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("could not listen on %s again: %v", addr, err)
+	}
+	server := &fakeRedis{
+		listener: listener,
+		data:     make(map[string]string),
+	}
+	go server.serve()
+	defer listener.Close()
+
+	cache.Add("https://example.com", []byte("testdata"))
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected a miss during the backoff")
+	}
+
+	server.mu.Lock()
+	commands := len(server.commands)
+	server.mu.Unlock()
+	if commands != 0 {
+		t.Errorf("expected no commands during the backoff, got %d", commands)
+	}
+}
@@ -0,0 +1,254 @@
+package pokecache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// usage totals the entries and bytes of every shard, so the size bounds
+// can be enforced across the whole cache
+type usage struct {
+	entries atomic.Int64
+	bytes   atomic.Int64
+}
+
+type shard struct {
+	entries map[string]*list.Element
+	// Most recently used entries are at the front
+	recency *list.List
+	bytes   int
+	mu      sync.Mutex
+
+	usage            *usage
+	staleWindow      time.Duration
+	revalidateWindow time.Duration
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+func newShard(usage *usage, staleWindow time.Duration, revalidateWindow time.Duration) *shard {
+	return &shard{
+		entries:          make(map[string]*list.Element),
+		recency:          list.New(),
+		usage:            usage,
+		staleWindow:      staleWindow,
+		revalidateWindow: revalidateWindow,
+	}
+}
+
+// add returns whether the entry was stored. Entries larger than maxBytes
+// are never stored, and the entry they would have replaced is returned as
+// evicted.
+func (s *shard) add(entry *cacheEntry, maxBytes int) (bool, *cacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var replaced *cacheEntry
+	if element, ok := s.entries[entry.key]; ok {
+		replaced = s.remove(element)
+	}
+
+	if maxBytes > 0 && entry.size() > maxBytes {
+		return false, replaced
+	}
+
+	s.entries[entry.key] = s.recency.PushFront(entry)
+	s.bytes += entry.size()
+	s.usage.entries.Add(1)
+	s.usage.bytes.Add(int64(entry.size()))
+	return true, nil
+}
+
+func (s *shard) getFresh(key string, now time.Time) (*cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok || !now.Before(element.Value.(*cacheEntry).expiresAt) {
+		s.misses++
+		return nil, false
+	}
+
+	s.hits++
+	s.recency.MoveToFront(element)
+	return element.Value.(*cacheEntry), true
+}
+
+func (s *shard) getStale(key string, now time.Time) (*cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if now.Before(entry.expiresAt) || !now.Before(entry.expiresAt.Add(s.staleWindow)) {
+		return nil, false
+	}
+
+	s.recency.MoveToFront(element)
+	return entry, true
+}
+
+// getExpired doesn't count towards hits or misses, the lookup that found
+// the entry expired already did
+func (s *shard) getExpired(key string, now time.Time) (*cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if now.Before(entry.expiresAt) || entry.validators.IsZero() {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (s *shard) validators(key string) (Validators, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return Validators{}, false
+	}
+
+	validators := element.Value.(*cacheEntry).validators
+	return validators, !validators.IsZero()
+}
+
+func (s *shard) delete(key string) (*cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	return s.remove(element), true
+}
+
+func (s *shard) clear() []*cacheEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cleared := make([]*cacheEntry, 0, len(s.entries))
+	for element := s.recency.Front(); element != nil; element = element.Next() {
+		cleared = append(cleared, element.Value.(*cacheEntry))
+	}
+
+	s.usage.entries.Add(-int64(len(s.entries)))
+	s.usage.bytes.Add(-int64(s.bytes))
+	s.entries = make(map[string]*list.Element)
+	s.recency.Init()
+	s.bytes = 0
+	return cleared
+}
+
+func (s *shard) stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Stats{
+		Hits:      s.hits,
+		Misses:    s.misses,
+		Evictions: s.evictions,
+		Entries:   len(s.entries),
+		Bytes:     s.bytes,
+	}
+}
+
+func (s *shard) entryInfos() []EntryInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]EntryInfo, 0, len(s.entries))
+	for element := s.recency.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*cacheEntry)
+		infos = append(infos, EntryInfo{
+			Key:       entry.key,
+			Size:      entry.size(),
+			CreatedAt: entry.createdAt,
+			ExpiresAt: entry.expiresAt,
+		})
+	}
+
+	return infos
+}
+
+func (s *shard) reap(now time.Time) []*cacheEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := []*cacheEntry{}
+	for _, element := range s.entries {
+		entry := element.Value.(*cacheEntry)
+		if s.retained(entry, now) {
+			continue
+		}
+		expired = append(expired, s.remove(element))
+		s.evictions++
+	}
+
+	return expired
+}
+
+// retained reports whether an entry is still fresh, within the stale
+// window, or can still be renewed with a conditional request
+func (s *shard) retained(entry *cacheEntry, now time.Time) bool {
+	if now.Before(entry.expiresAt.Add(s.staleWindow)) {
+		return true
+	}
+	return !entry.validators.IsZero() && now.Before(entry.expiresAt.Add(s.revalidateWindow))
+}
+
+// evictOldest evicts the least recently used entry other than key, the
+// entry being replaced
+func (s *shard) evictOldest(key string) (*cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element := s.recency.Back()
+	if element != nil && element.Value.(*cacheEntry).key == key {
+		element = element.Prev()
+	}
+	if element == nil {
+		return nil, false
+	}
+
+	s.evictions++
+	return s.remove(element), true
+}
+
+func (s *shard) size(key string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return 0, false
+	}
+	return element.Value.(*cacheEntry).size(), true
+}
+
+// Must be called with s.mu held
+func (s *shard) remove(element *list.Element) *cacheEntry {
+	entry := s.recency.Remove(element).(*cacheEntry)
+	delete(s.entries, entry.key)
+	s.bytes -= entry.size()
+	s.usage.entries.Add(-1)
+	s.usage.bytes.Add(-int64(entry.size()))
+	return entry
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -16,14 +20,22 @@ import (
 type commandConfig struct {
 	Next     *string
 	Previous *string
-	Cache    *pokecache.Cache
+	Client   *pokeapi.Client
 	Pokedex  map[string]pokeapi.PokemonResult
+
+	// Language and game version of the Pokedex entries shown by inspect
+	Language    string
+	GameVersion string
+
+	// Cache is every cache layer, MemoryCache only the in-memory one
+	Cache       pokecache.Cache
+	MemoryCache *pokecache.MemoryCache
 }
 
 type command struct {
 	name        string
 	description string
-	callback    func(*commandConfig, string) error
+	callback    func(context.Context, *commandConfig, string) error
 }
 
 func printPrompt() {
@@ -40,7 +52,7 @@ func getInput(scanner *bufio.Scanner) string {
 	return ""
 }
 
-func runCommand(input string, config *commandConfig) error {
+func runCommand(ctx context.Context, input string, config *commandConfig) error {
 	commandPart, arg, _ := strings.Cut(input, " ")
 
 	validCommands := getValidCommands()
@@ -49,7 +61,21 @@ func runCommand(input string, config *commandConfig) error {
 		return errors.New(fmt.Sprintf("invalid command %s", input))
 	}
 
-	return command.callback(config, arg)
+	return command.callback(ctx, config, arg)
+}
+
+// Ctrl+C cancels the running command instead of killing the process
+func runCommandWithTimeout(input string, config *commandConfig, timeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return runCommand(ctx, input, config)
 }
 
 func getValidCommands() map[string]command {
@@ -86,7 +112,7 @@ func getValidCommands() map[string]command {
 		},
 		"inspect": {
 			name:        "inspect",
-			description: "View the information of caught pokemon",
+			description: "View the information of caught pokemon, add --moves to list learnable moves",
 			callback:    commandInspect,
 		},
 		"pokedex": {
@@ -94,10 +120,35 @@ func getValidCommands() map[string]command {
 			description: "List all the caught pokemon",
 			callback:    commandPokedex,
 		},
+		"evolutions": {
+			name:        "evolutions",
+			description: "Show the evolution tree of the specified pokemon",
+			callback:    commandEvolutions,
+		},
+		"type": {
+			name:        "type",
+			description: "Show what the specified type is strong and weak against, and the pokemon of that type",
+			callback:    commandType,
+		},
+		"move": {
+			name:        "move",
+			description: "Show the details of the specified move",
+			callback:    commandMove,
+		},
+		"ability": {
+			name:        "ability",
+			description: "Show the effect of the specified ability and the pokemon that have it",
+			callback:    commandAbility,
+		},
+		"cache": {
+			name:        "cache",
+			description: "Inspect the cache: cache stats | cache list | cache clear | cache evict <url>",
+			callback:    commandCache,
+		},
 	}
 }
 
-func commandPokedex(config *commandConfig, _ string) error {
+func commandPokedex(_ context.Context, config *commandConfig, _ string) error {
 	fmt.Println("Your Pokedex:")
 	if len(config.Pokedex) == 0 {
 		fmt.Println("[No entries found]")
@@ -110,7 +161,17 @@ func commandPokedex(config *commandConfig, _ string) error {
 	return nil
 }
 
-func commandInspect(config *commandConfig, pokemonName string) error {
+func commandInspect(ctx context.Context, config *commandConfig, args string) error {
+	pokemonName := ""
+	showMoves := false
+	for _, arg := range strings.Fields(args) {
+		if arg == "--moves" {
+			showMoves = true
+		} else {
+			pokemonName = arg
+		}
+	}
+
 	if len(pokemonName) == 0 {
 		return errors.New("pokemonName cannot be empty")
 	}
@@ -121,9 +182,31 @@ func commandInspect(config *commandConfig, pokemonName string) error {
 	}
 
 	printPokemonData(data)
+
+	// The species details are extras, inspect still works offline from the
+	// Pokedex without them
+	species, err := config.Client.GetPokemonSpeciesContext(ctx, &data)
+	if err != nil {
+		fmt.Println("Warning: failed to get species:", err)
+	} else {
+		printSpeciesData(species, config.Language, config.GameVersion)
+	}
+
+	if showMoves {
+		printPokemonMoves(data)
+	}
 	return nil
 }
 
+func printSpeciesData(species *pokeapi.SpeciesResult, language string, version string) {
+	if genus := species.Genus(language); genus != "" {
+		fmt.Printf("Genus: %s\n", genus)
+	}
+	if flavorText, ok := species.FlavorText(language, version); ok {
+		fmt.Printf("Pokedex entry: %s\n", flavorText)
+	}
+}
+
 func printPokemonData(data pokeapi.PokemonResult) {
 	fmt.Printf("Name: %s\n", data.Name)
 	fmt.Printf("Height: %v\n", data.Height)
@@ -136,17 +219,28 @@ func printPokemonData(data pokeapi.PokemonResult) {
 	for _, t := range data.Types {
 		fmt.Printf("- %s\n", t.Type.Name)
 	}
+	fmt.Println("Abilities:")
+	for _, a := range data.Abilities {
+		if a.IsHidden {
+			fmt.Printf("- %s (hidden)\n", a.Ability.Name)
+		} else {
+			fmt.Printf("- %s\n", a.Ability.Name)
+		}
+	}
 }
 
-func commandCatch(config *commandConfig, pokemonName string) error {
+func commandCatch(ctx context.Context, config *commandConfig, pokemonName string) error {
 	if len(pokemonName) == 0 {
 		return errors.New("pokemonName cannot be empty")
 	}
 
 	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
-	result, err := pokeapi.GetPokemon(pokemonName, config.Cache)
+	result, err := config.Client.GetPokemonContext(ctx, pokemonName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such pokemon %s", pokemonName))
+	}
 	if err != nil {
-		return errors.New(fmt.Sprintf("Failed to get pokemon: %v", err))
+		return fmt.Errorf("Failed to get pokemon: %w", err)
 	}
 
 	caught := caughtPokemon(result.BaseExperience)
@@ -173,15 +267,18 @@ func caughtPokemon(baseExp int) bool {
 	return roll < target
 }
 
-func commandExplore(config *commandConfig, locationName string) error {
+func commandExplore(ctx context.Context, config *commandConfig, locationName string) error {
 	if len(locationName) == 0 {
 		return errors.New("locationName cannot be empty")
 	}
 
 	fmt.Printf("Exploring %s...\n", locationName)
-	result, err := pokeapi.GetLocationPokemon(locationName, config.Cache)
+	result, err := config.Client.GetLocationPokemonContext(ctx, locationName)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return errors.New(fmt.Sprintf("no such location %s", locationName))
+	}
 	if err != nil {
-		return errors.New(fmt.Sprintf("Failed to get location pokemon: %v", err))
+		return fmt.Errorf("Failed to get location pokemon: %w", err)
 	}
 
 	fmt.Println("Found Pokemon:")
@@ -192,14 +289,14 @@ func commandExplore(config *commandConfig, locationName string) error {
 	return nil
 }
 
-func commandMap(config *commandConfig, _ string) error {
+func commandMap(ctx context.Context, config *commandConfig, _ string) error {
 	if config.Next == nil {
 		return errors.New("Cannot go forward, already in last page")
 	}
 
-	result, err := pokeapi.GetLocations(config.Next, config.Cache)
+	result, err := config.Client.GetLocationsContext(ctx, config.Next)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Failed to get locations: %v", err))
+		return fmt.Errorf("Failed to get locations: %w", err)
 	}
 
 	config.Next = result.Next
@@ -210,13 +307,13 @@ func commandMap(config *commandConfig, _ string) error {
 	return nil
 }
 
-func commandMapBack(config *commandConfig, _ string) error {
+func commandMapBack(ctx context.Context, config *commandConfig, _ string) error {
 	if config.Previous == nil {
 		return errors.New("Cannot go back, already in first page")
 	}
-	result, err := pokeapi.GetLocations(config.Previous, config.Cache)
+	result, err := config.Client.GetLocationsContext(ctx, config.Previous)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Failed to get locations: %v", err))
+		return fmt.Errorf("Failed to get locations: %w", err)
 	}
 
 	config.Next = result.Next
@@ -227,7 +324,7 @@ func commandMapBack(config *commandConfig, _ string) error {
 	return nil
 }
 
-func commandHelp(_ *commandConfig, _ string) error {
+func commandHelp(_ context.Context, _ *commandConfig, _ string) error {
 	validCommands := getValidCommands()
 	fmt.Println("Usage:")
 	fmt.Println()
@@ -240,27 +337,108 @@ func commandHelp(_ *commandConfig, _ string) error {
 	return nil
 }
 
-func commandExit(_ *commandConfig, _ string) error {
+func commandExit(_ context.Context, _ *commandConfig, _ string) error {
 	fmt.Println("Exiting the Pokedex...")
 	return nil
 }
 
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexgo")
+}
+
 func main() {
+	baseURL := flag.String("base-url", pokeapi.DefaultBaseURL, "Base URL of the PokeAPI server")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout for each PokeAPI request")
+	commandTimeout := flag.Duration("command-timeout", 30*time.Second, "Timeout for each command, 0 to disable")
+	retries := flag.Int("retries", pokeapi.DefaultRetryPolicy.MaxAttempts-1, "Number of retries for failed PokeAPI requests")
+	rateLimit := flag.Float64("rate-limit", 10, "Maximum PokeAPI requests per second, 0 to disable")
+	burst := flag.Int("burst", 5, "Maximum burst of PokeAPI requests above the rate limit")
+	cacheMaxBytes := flag.Int("cache-max-bytes", 64<<20, "Maximum size of the in-memory cache, 0 for no limit")
+	notFoundTTL := flag.Duration("not-found-ttl", pokeapi.DefaultNotFoundTTL, "How long unknown pokemon and location names are remembered, 0 to disable")
+	cacheShards := flag.Int("cache-shards", 1, "Number of independently locked shards in the in-memory cache")
+	compressThreshold := flag.Int("cache-compress-threshold", 8<<10, "Cached values of at least this many bytes are compressed, 0 to disable")
+	staleWindow := flag.Duration("stale-window", time.Hour, "How long expired entries are served while being refreshed, 0 to disable")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "Directory for the on-disk cache, empty to disable")
+	diskCacheTTL := flag.Duration("disk-cache-ttl", 7*24*time.Hour, "Time before on-disk cache entries expire")
+	diskCacheMaxBytes := flag.Int64("disk-cache-max-bytes", 256<<20, "Maximum size of the on-disk cache, 0 for no limit")
+	redisAddr := flag.String("redis-addr", "", "Address of a Redis compatible server to use as a shared cache, empty to disable")
+	redisTTL := flag.Duration("redis-ttl", 24*time.Hour, "Time before Redis cache entries expire")
+	language := flag.String("language", "en", "Language of Pokedex entries")
+	gameVersion := flag.String("game-version", "", "Game version of Pokedex entries, such as red or scarlet, empty for the latest")
+	flag.Parse()
+
+	memory := pokecache.NewCache(5*time.Minute,
+		pokecache.WithMaxBytes(*cacheMaxBytes),
+		pokecache.WithCompression(*compressThreshold),
+		pokecache.WithShards(*cacheShards),
+		pokecache.WithStaleWhileRevalidate(*staleWindow),
+	)
+	defer memory.Close()
+	layers := []pokecache.Cache{memory}
+
+	if *redisAddr != "" {
+		redis := pokecache.NewRedisCache(*redisAddr, *redisTTL)
+		defer redis.Close()
+		layers = append(layers, redis)
+	}
+
+	if *cacheDir != "" {
+		disk, err := pokecache.NewDiskCache(*cacheDir, *diskCacheTTL, *diskCacheMaxBytes)
+		if err != nil {
+			fmt.Println("Error: disabling disk cache:", err)
+		} else {
+			disk.StartCleanup(5 * time.Minute)
+			defer disk.Close()
+			defer disk.Cleanup()
+			layers = append(layers, disk)
+		}
+	}
+
+	cache := pokecache.NewLayeredCache(layers...)
+
+	client := pokeapi.NewClient(
+		pokeapi.WithBaseURL(*baseURL),
+		pokeapi.WithCache(cache),
+		pokeapi.WithTimeout(*timeout),
+		pokeapi.WithNotFoundTTL(*notFoundTTL),
+		pokeapi.WithRateLimit(*rateLimit, *burst),
+		pokeapi.WithRetryPolicy(pokeapi.RetryPolicy{
+			MaxAttempts:    *retries + 1,
+			InitialBackoff: pokeapi.DefaultRetryPolicy.InitialBackoff,
+			MaxBackoff:     pokeapi.DefaultRetryPolicy.MaxBackoff,
+			Jitter:         pokeapi.DefaultRetryPolicy.Jitter,
+		}),
+	)
+
 	scanner := bufio.NewScanner(os.Stdin)
-	initialNext := "https://pokeapi.co/api/v2/location-area/"
+	initialNext := client.LocationsURL()
 	config := commandConfig{
 		Next:     &initialNext,
 		Previous: nil,
-		Cache:    pokecache.NewCache(5 * time.Minute),
+		Client:   client,
 		Pokedex:  make(map[string]pokeapi.PokemonResult),
+
+		Language:    *language,
+		GameVersion: *gameVersion,
+
+		Cache:       cache,
+		MemoryCache: memory,
 	}
 
 	for {
 		printPrompt()
 		textInput := getInput(scanner)
 
-		err := runCommand(textInput, &config)
-		if err != nil {
+		err := runCommandWithTimeout(textInput, &config, *commandTimeout)
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("Command cancelled")
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Println("Command timed out")
+		} else if err != nil {
 			fmt.Println("Error:", err)
 		}
 